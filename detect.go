@@ -0,0 +1,72 @@
+package iso8601
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Kind classifies an ISO 8601 string.
+type Kind int
+
+// These are the kinds of string recognized by Detect.
+const (
+	KindInvalid Kind = iota
+	KindDuration
+	KindWeek
+	KindOrdinal
+	KindInterval
+	KindDateTime
+)
+
+var kindNames = [...]string{
+	KindInvalid:  "invalid",
+	KindDuration: "duration",
+	KindWeek:     "week",
+	KindOrdinal:  "ordinal",
+	KindInterval: "interval",
+	KindDateTime: "datetime",
+}
+
+// String returns the lower-case name of k, e.g. "duration".
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return kindNames[KindInvalid]
+	}
+	return kindNames[k]
+}
+
+var (
+	detectWeekRe     = regexp.MustCompile(`^(?:[+-]\d{5,}|\d{4})-?W\d`)
+	detectOrdinalRe  = regexp.MustCompile(`^\d{4}-?\d{3}$`)
+	detectDateTimeRe = regexp.MustCompile(`^\d{4}-\d{2}(?:-\d{2}(?:T\S+)?)?$`)
+)
+
+// Detect classifies s by its shape, without fully parsing it, so that it can be
+// routed to the appropriate parser. The heuristics, in order, are:
+//   - anything containing a solidus (/) is an interval;
+//   - anything starting with P, optionally signed, is a duration;
+//   - YYYY-Www... or YYYYWww... is a week, as is one with an expanded,
+//     signed year (+YYYYYY-Www...);
+//   - YYYY-DDD or YYYYDDD is an ordinal date;
+//   - YYYY-MM, YYYY-MM-DD or YYYY-MM-DDT... is a datetime.
+//
+// Because only the shape is checked, a string of a detected Kind may still
+// fail to parse (e.g. 2021-W60), and forms outside those above, such as
+// basic-format dates or bare times, are reported as KindInvalid.
+func Detect(s string) Kind {
+	switch {
+	case s == "":
+		return KindInvalid
+	case strings.Contains(s, "/"):
+		return KindInterval
+	case strings.HasPrefix(strings.TrimLeft(s, "+-"), "P"):
+		return KindDuration
+	case detectWeekRe.MatchString(s):
+		return KindWeek
+	case detectOrdinalRe.MatchString(s):
+		return KindOrdinal
+	case detectDateTimeRe.MatchString(s):
+		return KindDateTime
+	}
+	return KindInvalid
+}
@@ -0,0 +1,34 @@
+package iso8601
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(KindDuration, Detect("P1DT1H"))
+	assert.Equal(KindWeek, Detect("2021-W03-1"))
+	assert.Equal(KindOrdinal, Detect("2020-366"))
+	assert.Equal(KindOrdinal, Detect("2020001"))
+	assert.Equal(KindWeek, Detect("2021W031"))
+	assert.Equal(KindWeek, Detect("+012021-W03"))
+	assert.Equal(KindWeek, Detect("-12021W035"))
+	assert.Equal(KindInvalid, Detect("20200101"))
+	assert.Equal(KindInvalid, Detect("+2021-W03"))
+
+	// each detected string goes to a parser that accepts it
+	_, err := ParseOrdinalDate("2020001")
+	assert.NoError(err)
+	_, err = ParseWeek("+012021-W03")
+	assert.NoError(err)
+	assert.Equal(KindInterval, Detect("2020-01-01T00:00:00Z/P1D"))
+	assert.Equal(KindDateTime, Detect("2020-01-01T12:00:00Z"))
+	assert.Equal(KindDateTime, Detect("2020-01-01"))
+	assert.Equal(KindInvalid, Detect("Meow"))
+	assert.Equal(KindInvalid, Detect(""))
+
+	assert.Equal("duration", KindDuration.String())
+	assert.Equal("invalid", Kind(42).String())
+}
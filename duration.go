@@ -0,0 +1,507 @@
+package iso8601
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrCalendarDuration is returned when a duration with calendar components
+// (years or months) is converted to a fixed length without an anchor date.
+var ErrCalendarDuration = errors.New("duration has calendar components (years or months) with no fixed length")
+
+// Duration represents an ISO 8601 duration component by component.
+// Unlike time.Duration it can hold calendar components, such as years and months,
+// whose length depends on the date they are applied to, and it isn't limited
+// to the ~292 years a time.Duration can represent.
+// Components are magnitudes; the sign of the duration as a whole is held in Negative.
+type Duration struct {
+	Negative bool
+	Years    float64
+	Months   float64
+	Weeks    float64
+	Days     float64
+	Hours    float64
+	Minutes  float64
+	Seconds  float64
+}
+
+// ParseISODuration parses an ISO 8601 string representing a duration,
+// and returns the resultant Duration, keeping every component as written.
+// The same formats as ParseDuration are accepted.
+func ParseISODuration(isoDuration string) (Duration, error) {
+	return parseISODuration(isoDuration, false)
+}
+
+// parseISODuration implements ParseISODuration; lenient is as for matchDuration.
+func parseISODuration(isoDuration string, lenient bool) (Duration, error) {
+	matches, negative, err := matchDuration(isoDuration, lenient)
+	if err != nil {
+		return Duration{}, err
+	}
+
+	var values [7]float64
+	for i, match := range matches[1:] {
+		if match == "" {
+			continue
+		}
+
+		values[i], err = strconv.ParseFloat(match, 64)
+		if err != nil {
+			return Duration{}, err
+		}
+	}
+
+	return durationFromValues(negative, values), nil
+}
+
+// durationFieldNames name the submatches returned by matchDuration,
+// and the values of durationFromValues.
+var durationFieldNames = [...]string{"years", "months", "weeks", "days", "hours", "minutes", "seconds"}
+
+// durationFromValues builds a Duration from its components,
+// ordered as durationFieldNames.
+func durationFromValues(negative bool, values [7]float64) Duration {
+	return Duration{
+		Negative: negative,
+		Years:    values[0],
+		Months:   values[1],
+		Weeks:    values[2],
+		Days:     values[3],
+		Hours:    values[4],
+		Minutes:  values[5],
+		Seconds:  values[6],
+	}
+}
+
+// values returns the components of d, ordered as durationFieldNames.
+func (d Duration) values() [7]float64 {
+	return [7]float64{d.Years, d.Months, d.Weeks, d.Days, d.Hours, d.Minutes, d.Seconds}
+}
+
+// ParseDurationFields parses an ISO 8601 string representing a duration, and returns
+// the value of each component present, keyed by "years", "months", "weeks", "days",
+// "hours", "minutes" or "seconds". Nothing is resolved or converted,
+// which makes this handy for showing what a duration string contains.
+// The values of a negative duration are negative.
+func ParseDurationFields(isoDuration string) (map[string]float64, error) {
+	matches, negative, err := matchDuration(isoDuration, false)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]float64)
+	for i, match := range matches[1:] {
+		if match == "" {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(match, 64)
+		if err != nil {
+			return nil, err
+		}
+		if negative {
+			value = -value
+		}
+		fields[durationFieldNames[i]] = value
+	}
+
+	return fields, nil
+}
+
+// durationDesignators are the designators of the components named by durationFieldNames,
+// as keyed by ParseDurationPresence; minutes are m to tell them apart from months.
+var durationDesignators = [...]byte{'Y', 'M', 'W', 'D', 'H', 'm', 'S'}
+
+// ParseDurationPresence parses an ISO 8601 string representing a duration like
+// ParseDuration, and also reports which components were written, whatever their
+// value, keyed by designator: Y, M, W, D, H and S, with minutes keyed by a lowercase m
+// so they aren't mistaken for months. This tells, say, P1M (whose months
+// ParseDuration ignores) apart from P0D. In the alternative format every
+// component but weeks is written.
+func ParseDurationPresence(isoDuration string) (time.Duration, map[byte]bool, error) {
+	matches, _, err := matchDuration(isoDuration, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	d, err := ParseDuration(isoDuration)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	present := make(map[byte]bool)
+	for i, match := range matches[1:] {
+		if match != "" {
+			present[durationDesignators[i]] = true
+		}
+	}
+	return d, present, nil
+}
+
+// DurationFromMap builds a Duration from a map using the keys of ParseDurationFields,
+// e.g. {"days": 3, "hours": 12}, as might come from a decoded config file.
+// Unknown keys and values that aren't finite are an error. Values may be negative,
+// as long as they all are, in which case the Duration is negative.
+func DurationFromMap(m map[string]float64) (Duration, error) {
+	var values [7]float64
+	negative, positive := false, false
+	for key, value := range m {
+		i := durationFieldIndex(key)
+		if i < 0 {
+			return Duration{}, fmt.Errorf("unknown duration field %q", key)
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return Duration{}, fmt.Errorf("duration field %q is not a finite number", key)
+		}
+
+		if value < 0 {
+			negative = true
+			value = -value
+		} else if value > 0 {
+			positive = true
+		}
+		values[i] = value
+	}
+	if negative && positive {
+		return Duration{}, errors.New("duration fields have mixed signs")
+	}
+
+	return durationFromValues(negative, values), nil
+}
+
+// ToMap returns the non-zero components of d keyed as by ParseDurationFields.
+// The values of a negative duration are negative.
+func (d Duration) ToMap() map[string]float64 {
+	m := make(map[string]float64)
+	for i, value := range d.values() {
+		if value != 0 {
+			m[durationFieldNames[i]] = d.sign() * value
+		}
+	}
+	return m
+}
+
+// durationFieldIndex returns the index of name in durationFieldNames, or -1.
+func durationFieldIndex(name string) int {
+	for i, fieldName := range durationFieldNames {
+		if fieldName == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// ToTimeDuration returns d as a time.Duration, treating a day as 24 hours
+// and a week as 7 days.
+// Each component is taken as the shortest decimal that represents it and summed
+// exactly, as ParseDuration does, so the result is the same as ParseDuration's
+// for the string d was parsed from.
+// ErrCalendarDuration is returned if d has years or months, and
+// ErrDurationRange if d is too long to be represented.
+func (d Duration) ToTimeDuration() (time.Duration, error) {
+	if d.Years != 0 || d.Months != 0 {
+		return 0, ErrCalendarDuration
+	}
+
+	var values [5]string
+	for i, value := range [5]float64{d.Weeks, d.Days, d.Hours, d.Minutes, d.Seconds} {
+		if value == 0 {
+			continue
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+			return 0, fmt.Errorf("duration component %v is not a finite magnitude", value)
+		}
+		values[i] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	return sumFixedComponents(values, d.Negative)
+}
+
+// IsZero reports whether every component of d is zero, whatever its sign.
+func (d Duration) IsZero() bool {
+	return d.values() == [7]float64{}
+}
+
+// FixedSeconds returns the length in seconds of the fixed part of d: its weeks,
+// days, hours, minutes and seconds, treating a day as 24 hours. The calendar
+// components, years and months, are ignored, so P1MT1H is 3600 seconds.
+func (d Duration) FixedSeconds() float64 {
+	return d.sign() * ((7*d.Weeks+d.Days)*24*60*60 + d.Hours*60*60 + d.Minutes*60 + d.Seconds)
+}
+
+// AddTo returns t plus d.
+// Years, months, weeks and days are applied with time.AddDate, so they follow the calendar
+// (P1M added to Jan 31 lands on Mar 2 or 3, as with AddDate). Fractional years
+// are carried into months; fractional months become a share of the days in the
+// month they land in; fractional days are 24 hours long.
+func (d Duration) AddTo(t time.Time) time.Time {
+	sign := d.sign()
+
+	months := sign * (d.Years*12 + d.Months)
+	wholeMonths := math.Trunc(months)
+	t = t.AddDate(0, int(wholeMonths), 0)
+
+	days := sign * (7*d.Weeks + d.Days)
+	if frac := months - wholeMonths; frac != 0 {
+		days += frac * float64(daysIn(t.Year(), t.Month()))
+	}
+	wholeDays := math.Trunc(days)
+	t = t.AddDate(0, 0, int(wholeDays))
+
+	seconds := (days-wholeDays)*24*60*60 + sign*(d.Hours*60*60+d.Minutes*60+d.Seconds)
+	return t.Add(secondsDuration(seconds))
+}
+
+// RoundTo returns d rounded to the nearest whole multiple of unit, e.g. to whole
+// days with Period{Days: 1} or whole months with Period{Months: 1}, when applied
+// to anchor. Both d and the multiples of unit are measured from anchor, so the
+// calendar decides: P1M15D from March 1 ends April 16, halfway between P1M and P2M.
+// Halfway values round away from zero, making that P2M.
+// The result has only the components of unit. If unit doesn't move time forward,
+// d is returned unchanged.
+func (d Duration) RoundTo(unit Period, anchor time.Time) Duration {
+	step := unit.AddTo(anchor)
+	if !step.After(anchor) {
+		return d
+	}
+
+	// estimate the number of units, then settle on k with k*unit <= end < (k+1)*unit
+	end := d.AddTo(anchor)
+	k := int(math.Floor(float64(end.Unix()-anchor.Unix()) / float64(step.Unix()-anchor.Unix())))
+	for unit.times(k).AddTo(anchor).After(end) {
+		k--
+	}
+	for !unit.times(k + 1).AddTo(anchor).After(end) {
+		k++
+	}
+
+	below, above := end.Sub(unit.times(k).AddTo(anchor)), unit.times(k+1).AddTo(anchor).Sub(end)
+	if below > above || (below == above && !end.Before(anchor)) {
+		k++
+	}
+
+	negative := k < 0
+	if negative {
+		k = -k
+	}
+	p := unit.times(k)
+	return Duration{
+		Negative: negative,
+		Years:    float64(p.Years),
+		Months:   float64(p.Months),
+		Days:     float64(p.Days),
+	}
+}
+
+// String returns d in the ISO 8601 designator format, e.g. P1Y2M3DT4H5M6.5S,
+// or -P1D for a negative duration.
+// Zero components are omitted; a zero duration is PT0S.
+// Weeks can't be combined with other components, so unless d has only weeks (P2W)
+// they are written as 7 days each, e.g. P9D for a week and two days.
+func (d Duration) String() string {
+	if d.Weeks != 0 && (Duration{Weeks: d.Weeks} != d.Abs()) {
+		d.Days += 7 * d.Weeks
+		d.Weeks = 0
+	}
+
+	var b strings.Builder
+	if d.Negative {
+		b.WriteString("-")
+	}
+	b.WriteString("P")
+	writeComponent(&b, d.Years, 'Y')
+	writeComponent(&b, d.Months, 'M')
+	writeComponent(&b, d.Weeks, 'W')
+	writeComponent(&b, d.Days, 'D')
+	if d.Hours != 0 || d.Minutes != 0 || d.Seconds != 0 {
+		b.WriteString("T")
+		writeComponent(&b, d.Hours, 'H')
+		writeComponent(&b, d.Minutes, 'M')
+		writeComponent(&b, d.Seconds, 'S')
+	}
+
+	if strings.HasSuffix(b.String(), "P") {
+		return "PT0S"
+	}
+	return b.String()
+}
+
+// FormatISODurationAlt returns d in the ISO 8601 alternative format,
+// PYYYY-MM-DDThh:mm:ss, zero-padded to four digits for years and two for
+// everything else, e.g. P0003-06-04T12:30:05.
+// The alternative format has no weeks, so they are written as 7 days each.
+// Only the seconds may carry a fraction; fractions of other components are truncated.
+func FormatISODurationAlt(d Duration) string {
+	seconds := strconv.FormatFloat(d.Seconds, 'f', -1, 64)
+	if d.Seconds < 10 {
+		seconds = "0" + seconds
+	}
+
+	sign := ""
+	if d.Negative {
+		sign = "-"
+	}
+
+	return fmt.Sprintf("%sP%04d-%02d-%02dT%02d:%02d:%s", sign,
+		int64(d.Years), int64(d.Months), int64(7*d.Weeks+d.Days),
+		int64(d.Hours), int64(d.Minutes), seconds)
+}
+
+// Neg returns d with its sign flipped. The zero duration is returned unchanged.
+func (d Duration) Neg() Duration {
+	if d.Abs() == (Duration{}) {
+		return Duration{}
+	}
+	d.Negative = !d.Negative
+	return d
+}
+
+// Abs returns the absolute value of d.
+func (d Duration) Abs() Duration {
+	d.Negative = false
+	return d
+}
+
+// Scale returns d with every component multiplied by factor, e.g. for proration.
+// Calendar components are scaled too, so the result may have fractional years or
+// months; those only resolve to a length in AddTo or the Total methods.
+// A negative factor flips the sign of the duration.
+func (d Duration) Scale(factor float64) Duration {
+	values := d.values()
+	for i := range values {
+		values[i] *= math.Abs(factor)
+	}
+	scaled := durationFromValues(d.Negative, values)
+	if factor < 0 {
+		return scaled.Neg()
+	}
+	if scaled.Abs() == (Duration{}) {
+		return Duration{}
+	}
+	return scaled
+}
+
+// sign returns -1 for negative durations, and 1 otherwise.
+func (d Duration) sign() float64 {
+	if d.Negative {
+		return -1
+	}
+	return 1
+}
+
+// writeComponent writes a non-zero duration component followed by its designator.
+func writeComponent(b *strings.Builder, value float64, designator byte) {
+	if value == 0 {
+		return
+	}
+	b.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	b.WriteByte(designator)
+}
+
+// secondsDuration converts a number of seconds to a time.Duration,
+// rounding to the nearest nanosecond.
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(math.Round(seconds * float64(time.Second)))
+}
+
+// Between returns the calendar duration from start to end, broken down into
+// years, months, days, hours, minutes and seconds, as in "29 years, 9 months, 24 days".
+// Days are borrowed from the month before end, with the day of month clamped
+// to that month's length, so Jan 31 to Mar 1 is P1M1D.
+// end is converted to start's location first, and times of day are compared
+// on the wall clock, so noon to noon the next day is P1D even across a daylight
+// saving change. If end is before start, the result is negative.
+func Between(start, end time.Time) Duration {
+	if end.Before(start) {
+		return Between(end, start).Neg()
+	}
+	end = end.In(start.Location())
+
+	sy, sm, sd := start.Date()
+	ey, em, ed := end.Date()
+	startClock, endClock := wallClock(start), wallClock(end)
+	if endClock < startClock {
+		// borrow a day for the time of day
+		ey, em, ed = time.Date(ey, em, ed-1, 0, 0, 0, 0, time.UTC).Date()
+		endClock += 24 * time.Hour
+	}
+
+	months := (ey*12 + int(em)) - (sy*12 + int(sm))
+	days := ed - sd
+	if months > 0 && days < 0 {
+		months--
+		// step forward whole months, clamping to the end of short months
+		y, m, _ := time.Date(sy, sm+time.Month(months), 1, 0, 0, 0, 0, time.UTC).Date()
+		d := sd
+		if dim := daysIn(y, m); d > dim {
+			d = dim
+		}
+		from := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		to := time.Date(ey, em, ed, 0, 0, 0, 0, time.UTC)
+		days = int(to.Sub(from).Hours() / 24)
+	}
+
+	clock := endClock - startClock
+	hours := clock / time.Hour
+	clock -= hours * time.Hour
+	minutes := clock / time.Minute
+	clock -= minutes * time.Minute
+
+	return Duration{
+		Years:   float64(months / 12),
+		Months:  float64(months % 12),
+		Days:    float64(days),
+		Hours:   float64(hours),
+		Minutes: float64(minutes),
+		Seconds: clock.Seconds(),
+	}
+}
+
+// wallClock returns the time of day shown on t's clock as a time.Duration since midnight.
+// Unlike the time elapsed since midnight, it isn't affected by daylight saving changes.
+func wallClock(t time.Time) time.Duration {
+	hour, min, sec := t.Clock()
+	return time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(t.Nanosecond())
+}
+
+// MonthsBetween returns the number of whole months from a to b, as for Between.
+// A month is only complete once b reaches a's day of month and time of day,
+// so Jan 31 to Feb 28 is 0 months, while Jan 15 to Feb 15 is 1.
+// If b is before a, the result is negative.
+func MonthsBetween(a, b time.Time) int {
+	d := Between(a, b)
+	return int(d.sign() * (d.Years*12 + d.Months))
+}
+
+// YearsBetween returns the number of whole years from a to b, as for MonthsBetween,
+// so Feb 29, 2020 to Feb 28, 2021 is 0 years.
+func YearsBetween(a, b time.Time) int {
+	return MonthsBetween(a, b) / 12
+}
+
+// TotalSeconds returns the length of d in seconds when applied to anchor.
+// Calendar components are resolved against anchor, so P1M is 31 days long
+// from January 1 but only 28 or 29 days from February 1.
+func (d Duration) TotalSeconds(anchor time.Time) float64 {
+	end := d.AddTo(anchor)
+
+	// go through Unix seconds, as Time.Sub saturates at ~292 years
+	return float64(end.Unix()-anchor.Unix()) +
+		float64(end.Nanosecond()-anchor.Nanosecond())/float64(time.Second)
+}
+
+// TotalMinutes returns the length of d in minutes when applied to anchor.
+// See TotalSeconds.
+func (d Duration) TotalMinutes(anchor time.Time) float64 {
+	return d.TotalSeconds(anchor) / 60
+}
+
+// TotalHours returns the length of d in hours when applied to anchor.
+// See TotalSeconds.
+func (d Duration) TotalHours(anchor time.Time) float64 {
+	return d.TotalSeconds(anchor) / (60 * 60)
+}
@@ -0,0 +1,450 @@
+package iso8601
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestISODurationStructParsing(t *testing.T) {
+	assert := assert.New(t)
+
+	d, err := ParseISODuration("P1Y2M3DT4H5M6.5S")
+	assert.NoError(err)
+	assert.Equal(Duration{Years: 1, Months: 2, Days: 3, Hours: 4, Minutes: 5, Seconds: 6.5}, d)
+	assert.Equal("P1Y2M3DT4H5M6.5S", d.String())
+
+	d, err = ParseISODuration("P0003-06-04T12:30:05")
+	assert.NoError(err)
+	assert.Equal(Duration{Years: 3, Months: 6, Days: 4, Hours: 12, Minutes: 30, Seconds: 5}, d)
+
+	d, err = ParseISODuration("P2W")
+	assert.NoError(err)
+	assert.Equal(Duration{Weeks: 2}, d)
+	assert.Equal("P2W", d.String())
+	dur, err := d.ToTimeDuration()
+	assert.NoError(err)
+	assert.Equal(14*24*time.Hour, dur)
+	assert.Equal("-P2W", d.Neg().String())
+
+	// weeks mixed with other components are written as days, so the result parses
+	for _, tc := range []struct {
+		d        Duration
+		expected string
+	}{
+		{Duration{Weeks: 1, Days: 2}, "P9D"},
+		{Duration{Negative: true, Weeks: 1, Hours: 3}, "-P7DT3H"},
+		{Duration{Years: 1, Weeks: 2}, "P1Y14D"},
+		{Duration{Weeks: 0.5, Days: 1}, "P4.5D"},
+	} {
+		assert.Equal(tc.expected, tc.d.String())
+		parsed, err := ParseISODuration(tc.d.String())
+		assert.NoError(err, tc.expected)
+		assert.Equal(tc.d.FixedSeconds(), parsed.FixedSeconds(), tc.expected)
+	}
+
+	_, err = ParseISODuration("P")
+	assert.Error(err)
+}
+
+func TestISODurationBeyondTimeDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	// far too long for a time.Duration, but fine as a Duration
+	d, err := ParseISODuration("P1000Y")
+	assert.NoError(err)
+	assert.Equal(Duration{Years: 1000}, d)
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(d.AddTo(start).Equal(start.AddDate(1000, 0, 0)))
+
+	_, err = d.ToTimeDuration()
+	assert.Equal(ErrCalendarDuration, err)
+
+	_, err = Duration{Days: 1000 * 366}.ToTimeDuration()
+	assert.Equal(ErrDurationRange, err)
+}
+
+func TestISODurationFixedSeconds(t *testing.T) {
+	assert := assert.New(t)
+
+	// years and months are ignored
+	d, err := ParseISODuration("P1MT1H")
+	assert.NoError(err)
+	assert.Equal(3600.0, d.FixedSeconds())
+
+	d, err = ParseISODuration("-P8DT1M0.5S")
+	assert.NoError(err)
+	assert.Equal(-(8*24*60*60 + 60.5), d.FixedSeconds())
+
+	assert.Equal(0.0, Duration{Years: 2}.FixedSeconds())
+}
+
+func TestISODurationIsZero(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(Duration{}.IsZero())
+	assert.True(Duration{Negative: true}.IsZero())
+	d, err := ParseISODuration("PT0S")
+	assert.NoError(err)
+	assert.True(d.IsZero())
+
+	assert.False(Duration{Seconds: 0.5}.IsZero())
+	assert.False(Duration{Weeks: 1}.IsZero())
+	assert.False(Duration{Negative: true, Years: 1}.IsZero())
+}
+
+func TestISODurationConversion(t *testing.T) {
+	assert := assert.New(t)
+
+	dur, err := Duration{Days: 1, Hours: 1, Seconds: 0.5}.ToTimeDuration()
+	assert.NoError(err)
+	assert.Equal(25*time.Hour+500*time.Millisecond, dur)
+
+	// exact, and over the same range as ParseDuration
+	for _, s := range []string{
+		"PT1000000H0.000000001S",
+		"PT2562047H47M16.854775807S",
+		"-PT2562047H47M16.854775808S",
+		"P2W",
+		"-P1DT0.1S",
+		"PT0.1234567891S",
+	} {
+		expected, err := ParseDuration(s)
+		assert.NoError(err, s)
+		d, err := ParseISODuration(s)
+		assert.NoError(err, s)
+		dur, err = d.ToTimeDuration()
+		assert.NoError(err, s)
+		assert.Equal(expected, dur, s)
+	}
+	dur, err = Duration{Hours: 1000000, Seconds: 0.000000001}.ToTimeDuration()
+	assert.NoError(err)
+	assert.Equal(time.Duration(3600000000000000001), dur)
+
+	for _, s := range []string{"PT2562047H47M16.854775808S", "-PT2562047H47M16.854775809S"} {
+		d, err := ParseISODuration(s)
+		assert.NoError(err, s)
+		_, err = d.ToTimeDuration()
+		assert.Equal(ErrDurationRange, err, s)
+	}
+	_, err = Duration{Hours: math.NaN()}.ToTimeDuration()
+	assert.Error(err)
+	_, err = Duration{Hours: -1}.ToTimeDuration()
+	assert.Error(err)
+
+	// calendar components follow the calendar
+	start := time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(Duration{Months: 1, Days: 1}.AddTo(start).Equal(time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC)))
+	assert.True(Duration{Years: 0.5}.AddTo(start).Equal(time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)))
+	// half of March
+	assert.True(Duration{Months: 1.5}.AddTo(start).Equal(time.Date(2020, 3, 16, 12, 0, 0, 0, time.UTC)))
+
+	assert.Equal("PT0S", Duration{}.String())
+	assert.Equal("P1D", Duration{Days: 1}.String())
+}
+
+func TestISODurationTimeSeparator(t *testing.T) {
+	assert := assert.New(t)
+
+	// no time components, no T
+	assert.Equal("P1D", Duration{Days: 1}.String())
+	assert.Equal("P1Y2M", Duration{Years: 1, Months: 2}.String())
+	assert.Equal("P1D", FormatDurationExtended(24*time.Hour))
+	assert.Equal("P1D", FormatDurationMinimal(24*time.Hour))
+
+	// and with them, the T is required
+	assert.Equal("P1DT2H", Duration{Days: 1, Hours: 2}.String())
+	assert.Equal("PT0.5S", Duration{Seconds: 0.5}.String())
+	assert.Equal("P1DT2H", FormatDurationExtended(26*time.Hour))
+
+	for _, s := range []string{"P1D", "P1DT2H"} {
+		d, err := ParseISODuration(s)
+		assert.NoError(err, s)
+		assert.Equal(s, d.String())
+	}
+	_, err := ParseISODuration("P1D2H")
+	assert.Error(err)
+}
+
+func TestBetween(t *testing.T) {
+	assert := assert.New(t)
+
+	// a birthday
+	born := time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC)
+	today := time.Date(2020, 3, 10, 0, 0, 0, 0, time.UTC)
+	assert.Equal(Duration{Years: 29, Months: 9, Days: 24}, Between(born, today))
+
+	// on the birthday itself
+	today = time.Date(2020, 5, 15, 0, 0, 0, 0, time.UTC)
+	assert.Equal(Duration{Years: 30}, Between(born, today))
+
+	// end-of-month borrowing
+	assert.Equal(Duration{Months: 1, Days: 1}, Between(
+		time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(Duration{Months: 1, Days: 1}, Between(
+		time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(Duration{Days: 28}, Between(
+		time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC)))
+
+	// the time of day borrows from the days
+	assert.Equal(Duration{Days: 1, Hours: 22, Seconds: 1.5}, Between(
+		time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 3, 10, 0, 1, 500000000, time.UTC)))
+
+	// backwards
+	assert.Equal(Duration{Negative: true, Years: 29, Months: 9, Days: 24}, Between(
+		time.Date(2020, 3, 10, 0, 0, 0, 0, time.UTC), born))
+
+	// times of day are compared on the wall clock, across daylight saving changes too
+	chicago, err := time.LoadLocation("America/Chicago")
+	assert.NoError(err)
+	assert.Equal(Duration{Days: 1}, Between(
+		time.Date(2021, 3, 13, 12, 0, 0, 0, chicago),
+		time.Date(2021, 3, 14, 12, 0, 0, 0, chicago)))
+	assert.Equal(Duration{Days: 1}, Between(
+		time.Date(2021, 11, 6, 12, 0, 0, 0, chicago),
+		time.Date(2021, 11, 7, 12, 0, 0, 0, chicago)))
+	assert.Equal(Duration{Months: 1, Hours: 1}, Between(
+		time.Date(2021, 2, 14, 11, 0, 0, 0, chicago),
+		time.Date(2021, 3, 14, 12, 0, 0, 0, chicago)))
+	assert.Equal(Duration{Negative: true, Days: 1}, Between(
+		time.Date(2021, 3, 14, 12, 0, 0, 0, chicago),
+		time.Date(2021, 3, 13, 12, 0, 0, 0, chicago)))
+}
+
+func TestMonthsYearsBetween(t *testing.T) {
+	assert := assert.New(t)
+
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	assert.Equal(1, MonthsBetween(date(2021, 1, 15), date(2021, 2, 15)))
+	assert.Equal(0, MonthsBetween(date(2021, 1, 15), date(2021, 2, 14)))
+	// the day of month isn't reached in February
+	assert.Equal(0, MonthsBetween(date(2021, 1, 31), date(2021, 2, 28)))
+	assert.Equal(1, MonthsBetween(date(2021, 1, 31), date(2021, 3, 1)))
+	// across a year boundary
+	assert.Equal(3, MonthsBetween(date(2020, 11, 10), date(2021, 2, 10)))
+	assert.Equal(-3, MonthsBetween(date(2021, 2, 10), date(2020, 11, 10)))
+	// the time of day counts too
+	assert.Equal(0, MonthsBetween(date(2021, 1, 15).Add(time.Hour), date(2021, 2, 15)))
+
+	assert.Equal(1, YearsBetween(date(2020, 6, 1), date(2021, 6, 1)))
+	assert.Equal(0, YearsBetween(date(2020, 6, 1), date(2021, 5, 31)))
+	assert.Equal(0, YearsBetween(date(2020, 2, 29), date(2021, 2, 28)))
+	assert.Equal(1, YearsBetween(date(2020, 2, 29), date(2021, 3, 1)))
+	assert.Equal(-10, YearsBetween(date(2030, 1, 1), date(2020, 1, 1)))
+
+	// a month that spans a daylight saving change is still complete
+	chicago, err := time.LoadLocation("America/Chicago")
+	assert.NoError(err)
+	assert.Equal(1, MonthsBetween(
+		time.Date(2021, 2, 14, 12, 0, 0, 0, chicago),
+		time.Date(2021, 3, 14, 12, 0, 0, 0, chicago)))
+	assert.Equal(1, YearsBetween(
+		time.Date(2020, 12, 1, 0, 0, 0, 0, chicago),
+		time.Date(2021, 12, 1, 0, 0, 0, 0, chicago)))
+}
+
+func TestISODurationRoundTo(t *testing.T) {
+	assert := assert.New(t)
+
+	mar1 := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	apr1 := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+	month, day := Period{Months: 1}, Period{Days: 1}
+
+	// April 16 is halfway through 30-day April, so it rounds up
+	assert.Equal(Duration{Months: 2}, Duration{Months: 1, Days: 15}.RoundTo(month, mar1))
+	// but from April 1, May 16 is nearer May 1 than June 1
+	assert.Equal(Duration{Months: 1}, Duration{Months: 1, Days: 15}.RoundTo(month, apr1))
+	assert.Equal(Duration{Months: 1}, Duration{Days: 20}.RoundTo(month, apr1))
+	assert.Equal(Duration{Years: 1}, Duration{Months: 11, Days: 20}.RoundTo(Period{Years: 1}, apr1))
+	assert.Equal(Duration{Negative: true, Months: 2}, Duration{Negative: true, Days: 45}.RoundTo(month, apr1))
+
+	assert.Equal(Duration{Days: 2}, Duration{Days: 1, Hours: 12}.RoundTo(day, apr1))
+	assert.Equal(Duration{Days: 1}, Duration{Days: 1, Hours: 11, Minutes: 59}.RoundTo(day, apr1))
+	assert.Equal(Duration{Days: 31}, Duration{Months: 1, Hours: 6}.RoundTo(day, mar1))
+	assert.Equal(Duration{Negative: true, Days: 2}, Duration{Negative: true, Days: 1, Hours: 12}.RoundTo(day, apr1))
+	assert.Equal(Duration{Days: 14}, Duration{Weeks: 2}.RoundTo(day, apr1))
+	assert.Equal(Duration{}, Duration{Hours: 11}.RoundTo(day, apr1))
+
+	// a unit that goes nowhere leaves d alone
+	d := Duration{Days: 1, Hours: 12}
+	assert.Equal(d, d.RoundTo(Period{}, apr1))
+}
+
+func TestISODurationTotals(t *testing.T) {
+	assert := assert.New(t)
+
+	month := Duration{Months: 1}
+
+	// January has 31 days
+	jan := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(31*24.0, month.TotalHours(jan))
+	assert.Equal(31*24*60.0, month.TotalMinutes(jan))
+	assert.Equal(31*24*60*60.0, month.TotalSeconds(jan))
+
+	// February 2021 has 28
+	feb := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(28*24.0, month.TotalHours(feb))
+
+	// mixed components
+	d := Duration{Months: 1, Hours: 1, Seconds: 0.5}
+	assert.Equal(31*24*60*60+60*60+0.5, d.TotalSeconds(jan))
+
+	// longer than a time.Duration can hold; 243 leap days from 2000 to 3000
+	assert.Equal((1000*365+243)*24.0, Duration{Years: 1000}.TotalHours(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestParseDurationFields(t *testing.T) {
+	assert := assert.New(t)
+
+	fields, err := ParseDurationFields("P1Y2M3DT4H5M6.5S")
+	assert.NoError(err)
+	assert.Equal(map[string]float64{
+		"years":   1,
+		"months":  2,
+		"days":    3,
+		"hours":   4,
+		"minutes": 5,
+		"seconds": 6.5,
+	}, fields)
+
+	// absent components are absent, explicit zeros are not
+	fields, err = ParseDurationFields("P0DT1H")
+	assert.NoError(err)
+	assert.Equal(map[string]float64{"days": 0, "hours": 1}, fields)
+
+	_, err = ParseDurationFields("P1H")
+	assert.Error(err)
+}
+
+func TestParseDurationPresence(t *testing.T) {
+	assert := assert.New(t)
+
+	d, present, err := ParseDurationPresence("P1Y2DT3S")
+	assert.NoError(err)
+	assert.Equal(2*24*time.Hour+3*time.Second, d)
+	assert.Equal(map[byte]bool{'Y': true, 'D': true, 'S': true}, present)
+
+	// months and minutes are told apart, and zeros are present
+	d, present, err = ParseDurationPresence("P1MT0M")
+	assert.NoError(err)
+	assert.Equal(time.Duration(0), d)
+	assert.Equal(map[byte]bool{'M': true, 'm': true}, present)
+
+	d, present, err = ParseDurationPresence("-P2W")
+	assert.NoError(err)
+	assert.Equal(-14*24*time.Hour, d)
+	assert.Equal(map[byte]bool{'W': true}, present)
+
+	_, present, err = ParseDurationPresence("P0003-06-04T12:30:05")
+	assert.NoError(err)
+	assert.Equal(map[byte]bool{'Y': true, 'M': true, 'D': true, 'H': true, 'm': true, 'S': true}, present)
+
+	_, present, err = ParseDurationPresence("P1H")
+	assert.Error(err)
+	assert.Nil(present)
+}
+
+func TestFormatISODurationAlt(t *testing.T) {
+	assert := assert.New(t)
+
+	d := Duration{Years: 3, Months: 6, Days: 4, Hours: 12, Minutes: 30, Seconds: 5}
+	assert.Equal("P0003-06-04T12:30:05", FormatISODurationAlt(d))
+
+	assert.Equal("P0000-00-00T00:00:05.5", FormatISODurationAlt(Duration{Seconds: 5.5}))
+	assert.Equal("P0000-00-00T00:00:00", FormatISODurationAlt(Duration{}))
+
+	// round trip
+	parsed, err := ParseISODuration(FormatISODurationAlt(d))
+	assert.NoError(err)
+	assert.Equal(d, parsed)
+}
+
+func TestISODurationNegAbs(t *testing.T) {
+	assert := assert.New(t)
+
+	day, err := ParseISODuration("P1D")
+	assert.NoError(err)
+	assert.Equal("-P1D", day.Neg().String())
+	assert.Equal("P1D", day.Neg().Neg().String())
+	assert.Equal("P1D", day.Abs().String())
+
+	hour, err := ParseISODuration("-PT1H")
+	assert.NoError(err)
+	assert.True(hour.Negative)
+	assert.Equal("-PT1H", hour.String())
+	assert.Equal("PT1H", hour.Abs().String())
+	assert.Equal("PT1H", hour.Neg().String())
+
+	// zero has no sign
+	assert.Equal(Duration{}, Duration{}.Neg())
+	assert.Equal(Duration{}, Duration{}.Abs())
+
+	// the sign is honored when the duration is used
+	dur, err := hour.ToTimeDuration()
+	assert.NoError(err)
+	assert.Equal(-time.Hour, dur)
+	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(Duration{Months: 1, Days: 1}.Neg().AddTo(start).Equal(time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC)))
+	assert.Equal("-P0000-00-01T00:00:00", FormatISODurationAlt(day.Neg()))
+
+	dur, err = ParseDuration("-P1DT1H")
+	assert.NoError(err)
+	assert.Equal(-25*time.Hour, dur)
+	_, err = ParseDuration("--P1D")
+	assert.Error(err)
+}
+
+func TestDurationMap(t *testing.T) {
+	assert := assert.New(t)
+
+	m := map[string]float64{"days": 3, "hours": 12}
+	d, err := DurationFromMap(m)
+	assert.NoError(err)
+	assert.Equal(Duration{Days: 3, Hours: 12}, d)
+	assert.Equal("P3DT12H", d.String())
+	assert.Equal(m, d.ToMap())
+
+	// negative durations have negative values
+	m = map[string]float64{"days": -3, "hours": -12}
+	d, err = DurationFromMap(m)
+	assert.NoError(err)
+	assert.Equal("-P3DT12H", d.String())
+	assert.Equal(m, d.ToMap())
+
+	_, err = DurationFromMap(map[string]float64{"fortnights": 1})
+	assert.Error(err)
+	_, err = DurationFromMap(map[string]float64{"days": 1, "hours": -1})
+	assert.Error(err)
+	for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, err = DurationFromMap(map[string]float64{"days": value})
+		assert.Error(err, value)
+	}
+
+	assert.Empty(Duration{}.ToMap())
+}
+
+func TestISODurationScale(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("PT1H", Duration{Hours: 2}.Scale(0.5).String())
+	assert.Equal("P3M", Duration{Months: 2}.Scale(1.5).String())
+
+	// calendar components can end up fractional
+	d := Duration{Months: 1}.Scale(0.5)
+	assert.Equal("P0.5M", d.String())
+	start := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(d.AddTo(start).Equal(time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC)))
+
+	// components are scaled independently, not normalized
+	assert.Equal("-P2DT60M", Duration{Days: 1, Minutes: 30}.Scale(-2).String())
+	assert.Equal(Duration{}, Duration{Negative: true, Days: 1}.Scale(0))
+}
@@ -0,0 +1,420 @@
+package iso8601
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Interval represents an ISO 8601 time interval.
+// Intervals are half-open: Start is included in the interval, End is not.
+// A zero Start or End stands for an open, unbounded end (see StartOpen and EndOpen),
+// which the methods of Interval treat as infinitely far in the past or future.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ParseInterval parses an ISO 8601 string representing a time interval,
+// and returns the resultant Interval. Three forms are accepted:
+// start/end, start/duration and duration/end, where start and end are
+// datetimes of any precision (see ParseReduced) and duration is applied as a
+// calendar duration (see Duration.AddTo). A reduced-precision datetime stands
+// for the start of its period, so 2020-01/2020-03 runs from January 1 to March 1.
+// In the start/end form, the end may be just a time with a leading T
+// (2020-01-01T09:00:00Z/T17:00:00Z), in which case it falls on the same
+// calendar date as the start and, if it has no zone, in the start's zone.
+// Either end, but not both, may be .. for an open end (2020-01-01T00:00:00Z/..),
+// in which case the other end must be a datetime, and the open end is left zero.
+// Whitespace around the solidus is ignored.
+func ParseInterval(isoInterval string) (Interval, error) {
+	parts := strings.Split(isoInterval, "/")
+	if len(parts) != 2 {
+		return Interval{}, errors.New("isoInterval string is of incorrect format")
+	}
+	first, second := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+	if first == openEnd || second == openEnd {
+		return parseOpenInterval(first, second)
+	}
+
+	if strings.HasPrefix(first, "P") {
+		dur, err := ParseISODuration(first)
+		if err != nil {
+			return Interval{}, err
+		}
+		end, _, err := ParseReduced(second)
+		if err != nil {
+			return Interval{}, err
+		}
+		return Interval{Start: dur.Neg().AddTo(end), End: end}, nil
+	}
+
+	start, _, err := ParseReduced(first)
+	if err != nil {
+		return Interval{}, err
+	}
+
+	if strings.HasPrefix(second, "P") {
+		dur, err := ParseISODuration(second)
+		if err != nil {
+			return Interval{}, err
+		}
+		return Interval{Start: start, End: dur.AddTo(start)}, nil
+	}
+
+	if strings.HasPrefix(second, "T") {
+		end, err := sameDayEnd(start, second)
+		if err != nil {
+			return Interval{}, err
+		}
+		return Interval{Start: start, End: end}, nil
+	}
+
+	end, _, err := ParseReduced(second)
+	if err != nil {
+		return Interval{}, err
+	}
+	return Interval{Start: start, End: end}, nil
+}
+
+// ParseIntervalFlexible parses an ISO 8601 string made up of a datetime and a
+// duration, in either order, and returns the resultant Interval. As in ParseInterval,
+// a datetime followed by a duration is the start, and a duration followed by
+// a datetime is the end. The duration may be negative, e.g. 2020-01-02/-P1D,
+// and the endpoints are swapped if need be, so that Start is never after End.
+func ParseIntervalFlexible(isoInterval string) (Interval, error) {
+	parts := strings.Split(isoInterval, "/")
+	if len(parts) != 2 {
+		return Interval{}, errors.New("isoInterval string is of incorrect format")
+	}
+	first, second := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+	isDuration := func(s string) bool {
+		return strings.HasPrefix(strings.TrimPrefix(s, "-"), "P")
+	}
+	durationFirst := isDuration(first)
+	if durationFirst == isDuration(second) {
+		return Interval{}, errors.New("isoInterval string must have one datetime and one duration")
+	}
+
+	isoDuration, isoDateTime := second, first
+	if durationFirst {
+		isoDuration, isoDateTime = first, second
+	}
+	dur, err := ParseISODuration(isoDuration)
+	if err != nil {
+		return Interval{}, err
+	}
+	t, _, err := ParseReduced(isoDateTime)
+	if err != nil {
+		return Interval{}, err
+	}
+
+	iv := Interval{Start: t, End: dur.AddTo(t)}
+	if durationFirst {
+		iv = Interval{Start: dur.Neg().AddTo(t), End: t}
+	}
+	if iv.End.Before(iv.Start) {
+		iv.Start, iv.End = iv.End, iv.Start
+	}
+	return iv, nil
+}
+
+// openEnd marks an open, unbounded end of an interval.
+const openEnd = ".."
+
+// parseOpenInterval parses the ends of an interval, exactly one of which is open.
+func parseOpenInterval(first, second string) (Interval, error) {
+	if first == openEnd && second == openEnd {
+		return Interval{}, errors.New("isoInterval string can't have two open ends")
+	}
+
+	var iv Interval
+	for _, end := range []struct {
+		value string
+		t     *time.Time
+	}{{first, &iv.Start}, {second, &iv.End}} {
+		if end.value == openEnd {
+			continue
+		}
+
+		t, _, err := ParseReduced(end.value)
+		if err != nil {
+			return Interval{}, err
+		}
+		*end.t = t
+	}
+	return iv, nil
+}
+
+// timeZoneRe matches the zone designator at the end of a time.
+var timeZoneRe = regexp.MustCompile(`(?:Z|[+-]\d{2}(?::?\d{2})?)$`)
+
+// sameDayEnd returns the time of day isoTime on start's calendar date,
+// in start's location unless isoTime has its own zone.
+func sameDayEnd(start time.Time, isoTime string) (time.Time, error) {
+	t, err := ParseTime(isoTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc := start.Location()
+	if timeZoneRe.MatchString(isoTime) {
+		loc = t.Location()
+	}
+	// ParseTime puts the end of the day, 24:00, on the following day
+	year, month, day := start.Date()
+	day += t.Day() - 1
+	return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc), nil
+}
+
+// Intersect returns the range covered by both iv and other,
+// and whether such a range exists.
+// Intervals that merely touch (one's End equals the other's Start)
+// do not intersect. The intersection only has an open end where both do.
+func (iv Interval) Intersect(other Interval) (Interval, bool) {
+	start := iv.Start
+	if startBefore(start, other.Start) {
+		start = other.Start
+	}
+
+	end := iv.End
+	if endBefore(other.End, end) {
+		end = other.End
+	}
+
+	overlap := Interval{Start: start, End: end}
+	if overlap.empty() {
+		return Interval{}, false
+	}
+	return overlap, true
+}
+
+// startBefore reports whether start a comes before start b,
+// an open start coming before any other.
+func startBefore(a, b time.Time) bool {
+	return !b.IsZero() && (a.IsZero() || a.Before(b))
+}
+
+// endBefore reports whether end a comes before end b,
+// an open end coming after any other.
+func endBefore(a, b time.Time) bool {
+	return !a.IsZero() && (b.IsZero() || a.Before(b))
+}
+
+// empty reports whether iv covers no time at all, i.e. whether it ends
+// at or before its start. An interval with an open end is never empty.
+func (iv Interval) empty() bool {
+	return !iv.StartOpen() && !iv.EndOpen() && !iv.Start.Before(iv.End)
+}
+
+// Difference returns the parts of iv not covered by other, in order:
+// none if other covers iv, two if other lies strictly within iv, and one otherwise.
+// Intervals that merely touch don't overlap, so iv is returned whole.
+// Open ends are kept where other doesn't reach them, so 2020-01-01T00:00:00Z/..
+// less 2021 is 2020 and everything from 2022 on.
+func (iv Interval) Difference(other Interval) []Interval {
+	overlap, ok := iv.Intersect(other)
+	if !ok {
+		if iv.empty() {
+			return nil
+		}
+		return []Interval{iv}
+	}
+
+	var diff []Interval
+	if startBefore(iv.Start, overlap.Start) {
+		diff = append(diff, Interval{Start: iv.Start, End: overlap.Start})
+	}
+	if endBefore(overlap.End, iv.End) {
+		diff = append(diff, Interval{Start: overlap.End, End: iv.End})
+	}
+	return diff
+}
+
+// OverlapDuration returns the length of time covered by both iv and other,
+// i.e. that of their intersection, or zero if they don't intersect.
+// An intersection with an open end is as long as a time.Duration can be.
+func (iv Interval) OverlapDuration(other Interval) time.Duration {
+	overlap, ok := iv.Intersect(other)
+	if !ok {
+		return 0
+	}
+	if overlap.StartOpen() || overlap.EndOpen() {
+		return math.MaxInt64
+	}
+	return overlap.End.Sub(overlap.Start)
+}
+
+// StartOpen reports whether iv has no lower bound, i.e. a zero Start.
+func (iv Interval) StartOpen() bool {
+	return iv.Start.IsZero()
+}
+
+// EndOpen reports whether iv has no upper bound, i.e. a zero End.
+func (iv Interval) EndOpen() bool {
+	return iv.End.IsZero()
+}
+
+// Contains reports whether t lies within iv: at or after Start and before End.
+// Open ends are unbounded, so 2020-01-01T00:00:00Z/.. contains every later time.
+func (iv Interval) Contains(t time.Time) bool {
+	return (iv.StartOpen() || !t.Before(iv.Start)) && (iv.EndOpen() || t.Before(iv.End))
+}
+
+// String returns the interval in the ISO 8601 start/end form.
+// Both endpoints are normalized to UTC, e.g. 2020-01-01T00:00:00Z/2020-01-02T00:00:00Z,
+// with open ends written as .. as accepted by ParseInterval.
+func (iv Interval) String() string {
+	return formatIntervalEnd(iv.Start) + "/" + formatIntervalEnd(iv.End)
+}
+
+// formatIntervalEnd returns t as an endpoint of Interval.String.
+func formatIntervalEnd(t time.Time) string {
+	if t.IsZero() {
+		return openEnd
+	}
+	return FormatDateTime(t.UTC(), time.RFC3339Nano)
+}
+
+// FormatWithDuration returns the interval in the ISO 8601 start/duration form,
+// e.g. 2020-01-01T09:00:00Z/PT1H30M. The start is normalized to UTC as in String.
+// The duration is given in hours, minutes and seconds, to the nanosecond,
+// so it doesn't depend on the calendar; sub-second lengths have a fractional
+// number of seconds. An interval with an open end has no length, so it's
+// written in the start/end form of String instead, e.g. 2020-01-01T09:00:00Z/..
+func (iv Interval) FormatWithDuration() string {
+	if iv.StartOpen() || iv.EndOpen() {
+		return iv.String()
+	}
+
+	sign, hours, minutes, seconds, nanos := splitDuration(iv.End.Sub(iv.Start))
+	dur := Duration{
+		Negative: sign != "",
+		Hours:    float64(hours),
+		Minutes:  float64(minutes),
+		Seconds:  float64(seconds) + float64(nanos)/float64(time.Second),
+	}
+	return FormatDateTime(iv.Start.UTC(), time.RFC3339Nano) + "/" + dur.String()
+}
+
+// Adjacent reports whether iv and other touch without overlapping,
+// i.e. one's End is exactly the other's Start. Open ends never touch.
+func (iv Interval) Adjacent(other Interval) bool {
+	touches := func(end, start time.Time) bool {
+		return !end.IsZero() && !start.IsZero() && end.Equal(start)
+	}
+	return touches(iv.End, other.Start) || touches(other.End, iv.Start)
+}
+
+// MergeIntervals returns the minimal set of intervals covering ivs,
+// sorted by start, open starts first. Overlapping and adjacent intervals are
+// coalesced; ivs itself is left unmodified.
+func MergeIntervals(ivs []Interval) []Interval {
+	sorted := make([]Interval, len(ivs))
+	copy(sorted, ivs)
+	sort.Slice(sorted, func(i, j int) bool {
+		return startBefore(sorted[i].Start, sorted[j].Start)
+	})
+
+	merged := make([]Interval, 0, len(sorted))
+	for _, iv := range sorted {
+		last := len(merged) - 1
+		// an open start always reaches back to the previous interval
+		if last >= 0 && (iv.StartOpen() || !endBefore(merged[last].End, iv.Start)) {
+			if endBefore(merged[last].End, iv.End) {
+				merged[last].End = iv.End
+			}
+			continue
+		}
+		merged = append(merged, iv)
+	}
+
+	return merged
+}
+
+// IntervalSet is a set of intervals, marshaling to and from JSON as an array
+// of ISO 8601 interval strings. Unmarshaling normalizes the set.
+type IntervalSet []Interval
+
+// Normalize returns s with overlapping and adjacent intervals merged,
+// sorted by start, as for MergeIntervals.
+func (s IntervalSet) Normalize() IntervalSet {
+	return IntervalSet(MergeIntervals(s))
+}
+
+// ClipToWeekdays returns the parts of iv that fall on Monday through Friday,
+// in order, as one interval per run of weekdays; Saturdays and Sundays are cut out.
+// Days are those of iv.Start's location. An interval that lies entirely within
+// a weekend gives none, as does one with an open end, whose weekdays never end.
+func (iv Interval) ClipToWeekdays() []Interval {
+	if iv.StartOpen() || iv.EndOpen() {
+		return nil
+	}
+
+	loc := iv.Start.Location()
+
+	var clipped []Interval
+	for cursor := iv.Start; cursor.Before(iv.End); {
+		year, month, day := cursor.Date()
+		// ISO numbering, Monday=1...Sunday=7
+		weekday := (int(cursor.Weekday())+6)%7 + 1
+		if weekday > 5 {
+			cursor = time.Date(year, month, day+8-weekday, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		end := time.Date(year, month, day+6-weekday, 0, 0, 0, 0, loc)
+		if end.After(iv.End) {
+			end = iv.End
+		}
+		clipped = append(clipped, Interval{Start: cursor, End: end})
+		cursor = end
+	}
+
+	return clipped
+}
+
+// SameLength reports whether iv and other are equally long,
+// to the nanosecond, regardless of when they occur.
+// Intervals with an open end are unbounded, and only the same length as each other.
+func (iv Interval) SameLength(other Interval) bool {
+	unbounded := iv.StartOpen() || iv.EndOpen()
+	if otherUnbounded := other.StartOpen() || other.EndOpen(); unbounded || otherUnbounded {
+		return unbounded == otherUnbounded
+	}
+	return iv.End.Sub(iv.Start) == other.End.Sub(other.Start)
+}
+
+// SplitByPeriod splits iv into consecutive buckets of length p, e.g. monthly
+// buckets for P1M, each respecting the calendar. The k-th bucket starts at
+// Start plus k times p, and the final bucket is clipped to End.
+// An error is returned if p doesn't move time forward, or iv has an open end.
+func (iv Interval) SplitByPeriod(p Period) ([]Interval, error) {
+	if iv.StartOpen() || iv.EndOpen() {
+		return nil, errors.New("can't split an interval with an open end")
+	}
+	if !p.AddTo(iv.Start).After(iv.Start) {
+		return nil, errors.New("period must be positive")
+	}
+
+	var buckets []Interval
+	for k := 0; ; k++ {
+		start := p.times(k).AddTo(iv.Start)
+		if !start.Before(iv.End) {
+			break
+		}
+
+		end := p.times(k + 1).AddTo(iv.Start)
+		if end.After(iv.End) {
+			end = iv.End
+		}
+		buckets = append(buckets, Interval{Start: start, End: end})
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,561 @@
+package iso8601
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestIntervalIntersect(t *testing.T) {
+	assert := assert.New(t)
+
+	jan1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	jan2 := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	jan3 := time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)
+	jan4 := time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	// overlapping intervals share Jan 2 - Jan 3
+	iv, ok := Interval{jan1, jan3}.Intersect(Interval{jan2, jan4})
+	assert.True(ok)
+	assert.True(iv.Start.Equal(jan2))
+	assert.True(iv.End.Equal(jan3))
+
+	// order shouldn't matter
+	iv, ok = Interval{jan2, jan4}.Intersect(Interval{jan1, jan3})
+	assert.True(ok)
+	assert.True(iv.Start.Equal(jan2))
+	assert.True(iv.End.Equal(jan3))
+
+	// touching intervals don't intersect
+	_, ok = Interval{jan1, jan2}.Intersect(Interval{jan2, jan3})
+	assert.False(ok)
+
+	// disjoint intervals don't intersect
+	_, ok = Interval{jan1, jan2}.Intersect(Interval{jan3, jan4})
+	assert.False(ok)
+}
+
+func TestIntervalOverlapDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	nine := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	at := func(hours float64) time.Time {
+		return nine.Add(time.Duration(hours * float64(time.Hour)))
+	}
+
+	// partial overlap
+	assert.Equal(90*time.Minute, Interval{at(0), at(3)}.OverlapDuration(Interval{at(1.5), at(5)}))
+	assert.Equal(90*time.Minute, Interval{at(1.5), at(5)}.OverlapDuration(Interval{at(0), at(3)}))
+
+	// full containment
+	assert.Equal(time.Hour, Interval{at(0), at(8)}.OverlapDuration(Interval{at(2), at(3)}))
+	assert.Equal(time.Hour, Interval{at(2), at(3)}.OverlapDuration(Interval{at(0), at(8)}))
+
+	// no overlap, including touching
+	assert.Equal(time.Duration(0), Interval{at(0), at(1)}.OverlapDuration(Interval{at(2), at(3)}))
+	assert.Equal(time.Duration(0), Interval{at(0), at(1)}.OverlapDuration(Interval{at(1), at(3)}))
+}
+
+func TestIntervalDifference(t *testing.T) {
+	assert := assert.New(t)
+
+	nine := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	at := func(hours float64) time.Time {
+		return nine.Add(time.Duration(hours * float64(time.Hour)))
+	}
+	iv := Interval{at(0), at(8)}
+
+	// fully covered, including exactly
+	assert.Empty(iv.Difference(Interval{at(-1), at(9)}))
+	assert.Empty(iv.Difference(iv))
+
+	// split in the middle
+	assert.Equal([]Interval{{at(0), at(2)}, {at(3), at(8)}}, iv.Difference(Interval{at(2), at(3)}))
+
+	// overlapping either end
+	assert.Equal([]Interval{{at(2), at(8)}}, iv.Difference(Interval{at(-1), at(2)}))
+	assert.Equal([]Interval{{at(2), at(8)}}, iv.Difference(Interval{at(0), at(2)}))
+	assert.Equal([]Interval{{at(0), at(6)}}, iv.Difference(Interval{at(6), at(9)}))
+	assert.Equal([]Interval{{at(0), at(6)}}, iv.Difference(Interval{at(6), at(8)}))
+
+	// disjoint or touching
+	assert.Equal([]Interval{iv}, iv.Difference(Interval{at(9), at(10)}))
+	assert.Equal([]Interval{iv}, iv.Difference(Interval{at(8), at(10)}))
+	assert.Equal([]Interval{iv}, iv.Difference(Interval{at(-2), at(0)}))
+
+	// an empty other takes nothing away
+	assert.Equal([]Interval{iv}, iv.Difference(Interval{at(4), at(4)}))
+
+	// an empty iv has nothing to give
+	assert.Empty(Interval{at(4), at(4)}.Difference(Interval{at(9), at(10)}))
+
+	// open ends are kept beyond other
+	y2020 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	y2021 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	jan2 := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	assert.Equal([]Interval{{y2020, y2021}, {Start: jan2}}, Interval{Start: y2020}.Difference(Interval{y2021, jan2}))
+	assert.Equal([]Interval{{End: y2021}, {Start: jan2}}, Interval{}.Difference(Interval{y2021, jan2}))
+	assert.Equal([]Interval{{End: y2020}}, Interval{End: y2021}.Difference(Interval{y2020, jan2}))
+	assert.Equal([]Interval{{Start: y2020}}, Interval{Start: y2020}.Difference(Interval{End: y2020}))
+
+	// and other's open ends take everything on their side
+	assert.Equal([]Interval{{y2020, y2021}}, Interval{Start: y2020}.Difference(Interval{Start: y2021}))
+	assert.Equal([]Interval{{y2021, jan2}}, Interval{y2020, jan2}.Difference(Interval{End: y2021}))
+	assert.Equal([]Interval{{End: y2020}}, Interval{End: y2021}.Difference(Interval{Start: y2020}))
+	assert.Empty(Interval{Start: y2021}.Difference(Interval{Start: y2020}))
+	assert.Empty(Interval{y2020, y2021}.Difference(Interval{}))
+	assert.Empty(Interval{}.Difference(Interval{}))
+}
+
+func TestIntervalString(t *testing.T) {
+	assert := assert.New(t)
+
+	iv := Interval{
+		Start: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2020, 1, 2, 12, 30, 0, 500000000, time.UTC),
+	}
+	assert.Equal("2020-01-01T00:00:00Z/2020-01-02T12:30:00.5Z", iv.String())
+
+	// non-UTC endpoints are normalized to Z
+	est := time.FixedZone("EST", -5*60*60)
+	iv = Interval{
+		Start: time.Date(2019, 12, 31, 19, 0, 0, 0, est),
+		End:   time.Date(2020, 1, 1, 19, 0, 0, 0, est),
+	}
+	assert.Equal("2020-01-01T00:00:00Z/2020-01-02T00:00:00Z", iv.String())
+}
+
+func TestIntervalFormatWithDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	iv := Interval{start, start.Add(90 * time.Minute)}
+	assert.Equal("2020-01-01T09:00:00Z/PT1H30M", iv.FormatWithDuration())
+
+	// sub-second lengths
+	iv = Interval{start, start.Add(1500 * time.Millisecond)}
+	assert.Equal("2020-01-01T09:00:00Z/PT1.5S", iv.FormatWithDuration())
+	iv = Interval{start, start.Add(250 * time.Microsecond)}
+	assert.Equal("2020-01-01T09:00:00Z/PT0.00025S", iv.FormatWithDuration())
+
+	// days are written as hours
+	iv = Interval{start, start.Add(49 * time.Hour)}
+	assert.Equal("2020-01-01T09:00:00Z/PT49H", iv.FormatWithDuration())
+
+	// parses back
+	parsed, err := ParseInterval(iv.FormatWithDuration())
+	assert.NoError(err)
+	assert.Equal(iv.String(), parsed.String())
+
+	assert.Equal("2020-01-01T09:00:00Z/PT0S", Interval{start, start}.FormatWithDuration())
+
+	// open ends have no length, so they're written as ..
+	for _, iv := range []Interval{{Start: start}, {End: start}} {
+		s := iv.FormatWithDuration()
+		assert.Equal(iv.String(), s)
+		parsed, err := ParseInterval(s)
+		assert.NoError(err, s)
+		assert.Equal(iv.String(), parsed.String())
+	}
+	assert.Equal("2020-01-01T09:00:00Z/..", Interval{Start: start}.FormatWithDuration())
+	assert.Equal("../2020-01-01T09:00:00Z", Interval{End: start}.FormatWithDuration())
+}
+
+func TestIntervalAdjacent(t *testing.T) {
+	assert := assert.New(t)
+
+	jan1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	jan2 := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	jan3 := time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)
+	jan4 := time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	// adjacent, either way round
+	assert.True(Interval{jan1, jan2}.Adjacent(Interval{jan2, jan3}))
+	assert.True(Interval{jan2, jan3}.Adjacent(Interval{jan1, jan2}))
+
+	// overlapping intervals aren't adjacent
+	assert.False(Interval{jan1, jan3}.Adjacent(Interval{jan2, jan4}))
+
+	// neither are intervals with a gap between them
+	assert.False(Interval{jan1, jan2}.Adjacent(Interval{jan3, jan4}))
+}
+
+func TestMergeIntervals(t *testing.T) {
+	assert := assert.New(t)
+
+	day := func(d int) time.Time {
+		return time.Date(2020, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	// overlapping, out of order
+	merged := MergeIntervals([]Interval{{day(3), day(6)}, {day(1), day(4)}, {day(4), day(5)}})
+	assert.Equal([]Interval{{day(1), day(6)}}, merged)
+
+	// adjacent
+	merged = MergeIntervals([]Interval{{day(1), day(2)}, {day(2), day(3)}})
+	assert.Equal([]Interval{{day(1), day(3)}}, merged)
+
+	// disjoint intervals pass through, sorted
+	merged = MergeIntervals([]Interval{{day(5), day(6)}, {day(1), day(2)}, {day(3), day(4)}})
+	assert.Equal([]Interval{{day(1), day(2)}, {day(3), day(4)}, {day(5), day(6)}}, merged)
+
+	// empty in, empty out
+	assert.Empty(MergeIntervals(nil))
+	assert.Empty(MergeIntervals([]Interval{}))
+}
+
+func TestIntervalSetNormalize(t *testing.T) {
+	assert := assert.New(t)
+
+	day := func(d int) time.Time {
+		return time.Date(2020, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	set := IntervalSet{{day(5), day(6)}, {day(1), day(3)}, {day(2), day(4)}}
+	assert.Equal(IntervalSet{{day(1), day(4)}, {day(5), day(6)}}, set.Normalize())
+	// the receiver is left as is
+	assert.Equal(Interval{day(5), day(6)}, set[0])
+}
+
+func TestIntervalParsing(t *testing.T) {
+	assert := assert.New(t)
+
+	jan1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	jan2 := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	for _, s := range []string{
+		"2020-01-01T00:00:00Z/2020-01-02T00:00:00Z",
+		"2020-01-01T00:00:00Z/P1D",
+		"P1D/2020-01-02T00:00:00Z",
+		"PT24H/2020-01-02T00:00:00Z",
+		"2019-12-31T19:00:00-05:00/2020-01-02T00:00:00Z",
+		// spaces around the solidus
+		"2020-01-01T00:00:00Z / 2020-01-02T00:00:00Z",
+		"2020-01-01T00:00:00Z\t/P1D",
+	} {
+		iv, err := ParseInterval(s)
+		assert.NoError(err, s)
+		assert.True(iv.Start.Equal(jan1), s)
+		assert.True(iv.End.Equal(jan2), s)
+	}
+
+	// whitespace inside an endpoint is still invalid
+	_, err := ParseInterval("2020-01-01 T00:00:00Z/2020-01-02T00:00:00Z")
+	assert.Error(err)
+	_, err = ParseInterval("2020-01-01T00:00:00Z")
+	assert.Error(err)
+	_, err = ParseInterval("P1D/P1D")
+	assert.Error(err)
+}
+
+func TestIntervalParsingZoneForms(t *testing.T) {
+	assert := assert.New(t)
+
+	// 2020-01-01T00:00:00Z to 2020-01-02T00:00:00Z, in each zone form ParseZoned accepts
+	for _, zone := range []struct {
+		start, end string
+	}{
+		{"2020-01-01T00:00:00Z", "2020-01-02T00:00:00Z"},
+		{"2020-01-01T01:00:00+01:00", "2020-01-02T01:00:00+01:00"},
+		{"2020-01-01T01:00:00+0100", "2020-01-02T01:00:00+0100"},
+		{"2020-01-01T01:00:00+01", "2020-01-02T01:00:00+01"},
+		{"2019-12-31T19:00:00-05", "2020-01-01T19:00:00-05"},
+		{"2020-01-01T01:00:00.000+0100", "2020-01-02T01:00:00,000+01"},
+	} {
+		for _, s := range []string{
+			zone.start + "/" + zone.end,
+			zone.start + "/P1D",
+			"P1D/" + zone.end,
+		} {
+			iv, err := ParseInterval(s)
+			if assert.NoError(err, s) {
+				assert.Equal("2020-01-01T00:00:00Z/2020-01-02T00:00:00Z", iv.String(), s)
+			}
+		}
+	}
+
+	// reduced precision times of day take them too
+	for _, s := range []string{"2020-01-01T01+01/2020-01-02T01+0100", "2020-01-01T01:00+0100/2020-01-02T01:00+01"} {
+		iv, err := ParseInterval(s)
+		if assert.NoError(err, s) {
+			assert.Equal("2020-01-01T00:00:00Z/2020-01-02T00:00:00Z", iv.String(), s)
+		}
+	}
+}
+
+func TestIntervalParsingFlexible(t *testing.T) {
+	assert := assert.New(t)
+
+	jan1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	jan2 := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	// either order, same interval
+	for _, s := range []string{
+		"2020-01-01T00:00:00Z/P1D",
+		"P1D/2020-01-02T00:00:00Z",
+		"P1D / 2020-01-02",
+		// negative durations are turned around
+		"2020-01-02T00:00:00Z/-P1D",
+		"-P1D/2020-01-01T00:00:00Z",
+	} {
+		iv, err := ParseIntervalFlexible(s)
+		assert.NoError(err, s)
+		assert.True(iv.Start.Equal(jan1), s)
+		assert.True(iv.End.Equal(jan2), s)
+	}
+
+	for _, s := range []string{
+		"2020-01-01T00:00:00Z/2020-01-02T00:00:00Z",
+		"P1D/P1D",
+		"P1D",
+		"P1X/2020-01-02T00:00:00Z",
+		"P1D/2020-13-02",
+	} {
+		_, err := ParseIntervalFlexible(s)
+		assert.Error(err, s)
+	}
+}
+
+func TestIntervalOpenEnds(t *testing.T) {
+	assert := assert.New(t)
+
+	jan1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	farFuture := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	iv, err := ParseInterval("2020-01-01T00:00:00Z/..")
+	assert.NoError(err)
+	assert.True(iv.Start.Equal(jan1))
+	assert.False(iv.StartOpen())
+	assert.True(iv.EndOpen())
+	assert.True(iv.Contains(jan1))
+	assert.True(iv.Contains(farFuture))
+	assert.False(iv.Contains(jan1.Add(-time.Nanosecond)))
+	assert.Equal("2020-01-01T00:00:00Z/..", iv.String())
+
+	iv, err = ParseInterval(".. / 2020-01-01")
+	assert.NoError(err)
+	assert.True(iv.StartOpen())
+	assert.True(iv.End.Equal(jan1))
+	assert.True(iv.Contains(time.Date(1, 1, 1, 0, 0, 0, 1, time.UTC)))
+	assert.False(iv.Contains(jan1))
+	assert.Equal("../2020-01-01T00:00:00Z", iv.String())
+
+	// an interval open at both ends contains everything, but can't be parsed
+	assert.True(Interval{}.Contains(farFuture))
+	_, err = ParseInterval("../..")
+	assert.Error(err)
+
+	// bounded intervals are unaffected
+	iv = Interval{jan1, jan1.AddDate(0, 0, 1)}
+	assert.True(iv.Contains(jan1))
+	assert.False(iv.Contains(iv.End))
+
+	// the other end can't be a duration
+	_, err = ParseInterval("../P1D")
+	assert.Error(err)
+	_, err = ParseInterval("P1D/..")
+	assert.Error(err)
+	_, err = ParseInterval("2020-01-01T00:00:00Z/.")
+	assert.Error(err)
+}
+
+func TestIntervalOpenEndsOperations(t *testing.T) {
+	assert := assert.New(t)
+
+	jan1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	jun1 := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	y2021 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	y2022 := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	from2020 := Interval{Start: jan1}
+	until2021 := Interval{End: y2021}
+	in2021 := Interval{y2021, y2022}
+
+	// open ends overlap everything on their side
+	assert.False(from2020.Adjacent(until2021))
+	assert.False(until2021.Adjacent(from2020))
+	assert.False(Interval{Start: jan1}.Adjacent(Interval{Start: y2021}))
+	assert.True(until2021.Adjacent(in2021))
+	assert.True(Interval{Start: y2022}.Adjacent(in2021))
+
+	iv, ok := from2020.Intersect(until2021)
+	assert.True(ok)
+	assert.Equal(Interval{jan1, y2021}, iv)
+	iv, ok = from2020.Intersect(in2021)
+	assert.True(ok)
+	assert.Equal(in2021, iv)
+	iv, ok = from2020.Intersect(Interval{Start: jun1})
+	assert.True(ok)
+	assert.Equal(Interval{Start: jun1}, iv)
+	iv, ok = until2021.Intersect(Interval{End: jun1})
+	assert.True(ok)
+	assert.Equal(Interval{End: jun1}, iv)
+	_, ok = until2021.Intersect(in2021)
+	assert.False(ok)
+	_, ok = Interval{End: jan1}.Intersect(Interval{Start: jan1})
+	assert.False(ok)
+
+	assert.Equal(y2021.Sub(jan1), from2020.OverlapDuration(until2021))
+	assert.Equal(y2022.Sub(y2021), from2020.OverlapDuration(in2021))
+	assert.Equal(time.Duration(math.MaxInt64), from2020.OverlapDuration(Interval{Start: jun1}))
+	assert.Equal(time.Duration(0), until2021.OverlapDuration(in2021))
+
+	// open starts sort first, and open ends swallow what follows
+	assert.Equal([]Interval{{}}, MergeIntervals([]Interval{from2020, until2021}))
+	assert.Equal([]Interval{{End: y2022}}, MergeIntervals([]Interval{in2021, until2021}))
+	assert.Equal([]Interval{{Start: jan1}}, MergeIntervals([]Interval{in2021, {Start: jun1}, {jan1, jun1}}))
+	assert.Equal([]Interval{{End: jan1}, {jun1, y2021}}, MergeIntervals([]Interval{{jun1, y2021}, {End: jan1}}))
+	assert.Equal(IntervalSet{{Start: jan1}}, IntervalSet{in2021, from2020}.Normalize())
+	// open starts overlap each other
+	assert.Equal([]Interval{{End: y2021}}, MergeIntervals([]Interval{{End: jan1}, until2021}))
+	assert.Equal([]Interval{{End: y2021}}, MergeIntervals([]Interval{until2021, {End: jan1}}))
+	assert.Equal([]Interval{{}}, MergeIntervals([]Interval{{End: jan1}, {}}))
+	assert.Equal(IntervalSet{{End: y2021}}, IntervalSet{{End: jan1}, until2021}.Normalize())
+
+	// unbounded intervals are only as long as each other
+	assert.True(from2020.SameLength(until2021))
+	assert.False(from2020.SameLength(in2021))
+	assert.False(in2021.SameLength(until2021))
+
+	assert.Empty(from2020.ClipToWeekdays())
+	assert.Empty(until2021.ClipToWeekdays())
+	_, err := from2020.SplitByPeriod(Period{Months: 1})
+	assert.Error(err)
+	_, err = until2021.SplitByPeriod(Period{Months: 1})
+	assert.Error(err)
+}
+
+func TestIntervalClipToWeekdays(t *testing.T) {
+	assert := assert.New(t)
+
+	at := func(d, h int) time.Time {
+		return time.Date(2020, 1, d, h, 0, 0, 0, time.UTC)
+	}
+
+	// noon Wednesday, Jan 1 to noon Saturday, Jan 11
+	clipped := Interval{at(1, 12), at(11, 12)}.ClipToWeekdays()
+	assert.Equal([]Interval{
+		{at(1, 12), at(4, 0)},
+		{at(6, 0), at(11, 0)},
+	}, clipped)
+
+	// starting at the weekend, ending midweek
+	clipped = Interval{at(4, 9), at(8, 17)}.ClipToWeekdays()
+	assert.Equal([]Interval{{at(6, 0), at(8, 17)}}, clipped)
+
+	// within a single weekday
+	clipped = Interval{at(7, 9), at(7, 17)}.ClipToWeekdays()
+	assert.Equal([]Interval{{at(7, 9), at(7, 17)}}, clipped)
+
+	// a weekend alone leaves nothing
+	assert.Empty(Interval{at(4, 0), at(6, 0)}.ClipToWeekdays())
+	assert.Empty(Interval{at(4, 10), at(5, 20)}.ClipToWeekdays())
+
+	// days follow the start's location
+	tokyo := time.FixedZone("JST", 9*60*60)
+	friday := time.Date(2020, 1, 3, 20, 0, 0, 0, tokyo)
+	clipped = Interval{friday, friday.Add(6 * time.Hour)}.ClipToWeekdays()
+	assert.Len(clipped, 1)
+	assert.True(clipped[0].End.Equal(time.Date(2020, 1, 4, 0, 0, 0, 0, tokyo)))
+}
+
+func TestIntervalSameLength(t *testing.T) {
+	assert := assert.New(t)
+
+	morning := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	evening := time.Date(2020, 6, 1, 18, 0, 0, 0, time.UTC)
+
+	assert.True(Interval{morning, morning.Add(time.Hour)}.SameLength(Interval{evening, evening.Add(time.Hour)}))
+	assert.False(Interval{morning, morning.Add(time.Hour)}.SameLength(Interval{evening, evening.Add(time.Hour + time.Nanosecond)}))
+	assert.False(Interval{morning, morning.Add(time.Hour)}.SameLength(Interval{evening, evening.Add(2 * time.Hour)}))
+}
+
+func TestIntervalParsingTimeOnlyEnd(t *testing.T) {
+	assert := assert.New(t)
+
+	iv, err := ParseInterval("2020-01-01T09:00:00Z/T17:00:00Z")
+	assert.NoError(err)
+	assert.True(iv.Start.Equal(time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)))
+	assert.True(iv.End.Equal(time.Date(2020, 1, 1, 17, 0, 0, 0, time.UTC)))
+	assert.Equal(8*time.Hour, iv.End.Sub(iv.Start))
+
+	// without a zone, the end takes the start's
+	iv, err = ParseInterval("2020-01-01T09:00:00-05:00/T17:00")
+	assert.NoError(err)
+	assert.Equal(8*time.Hour, iv.End.Sub(iv.Start))
+
+	// the end of the day is the next midnight
+	iv, err = ParseInterval("2020-01-01T09:00:00Z/T24:00")
+	assert.NoError(err)
+	assert.True(iv.End.Equal(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)))
+
+	_, err = ParseInterval("2020-01-01T09:00:00Z/T")
+	assert.Error(err)
+}
+
+func TestIntervalParsingReduced(t *testing.T) {
+	assert := assert.New(t)
+
+	// each endpoint is the start of its month
+	iv, err := ParseInterval("2020-01/2020-03")
+	assert.NoError(err)
+	assert.True(iv.Start.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(iv.End.Equal(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)))
+
+	iv, err = ParseInterval("2020-01-01/P1M")
+	assert.NoError(err)
+	assert.True(iv.End.Equal(time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestIntervalParsingDurationBeforeReducedEnd(t *testing.T) {
+	assert := assert.New(t)
+
+	// the end is the start of 2025, and a calendar year before it is the start of 2024
+	iv, err := ParseInterval("P1Y/2025")
+	assert.NoError(err)
+	assert.True(iv.Start.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(iv.End.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	// a leap year is still one calendar year
+	iv, err = ParseInterval("P1Y/2021")
+	assert.NoError(err)
+	assert.True(iv.Start.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(366*24*time.Hour, iv.End.Sub(iv.Start))
+
+	iv, err = ParseInterval("P1M/2020-03")
+	assert.NoError(err)
+	assert.True(iv.Start.Equal(time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestIntervalSplitByPeriod(t *testing.T) {
+	assert := assert.New(t)
+
+	jan1 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	iv := Interval{jan1, jan1.AddDate(0, 0, 75)}
+
+	buckets, err := iv.SplitByPeriod(Period{Months: 1})
+	assert.NoError(err)
+	assert.Equal([]Interval{
+		{time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)},
+		// clipped to the end
+		{time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 17, 0, 0, 0, 0, time.UTC)},
+	}, buckets)
+
+	// exact fit
+	buckets, err = Interval{jan1, jan1.AddDate(2, 0, 0)}.SplitByPeriod(Period{Years: 1})
+	assert.NoError(err)
+	assert.Len(buckets, 2)
+
+	// buckets don't drift after short months
+	jan31 := time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC)
+	buckets, err = Interval{jan31, time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)}.SplitByPeriod(Period{Months: 1})
+	assert.NoError(err)
+	assert.True(buckets[2].Start.Equal(time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC)))
+
+	_, err = iv.SplitByPeriod(Period{})
+	assert.Error(err)
+	_, err = iv.SplitByPeriod(Period{Months: -1})
+	assert.Error(err)
+}
@@ -2,6 +2,8 @@ package iso8601
 
 import (
 	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
 	"testing"
 	"time"
 )
@@ -33,6 +35,61 @@ func TestOrdinalDateParsing(t *testing.T) {
 	assert.Error(err)
 }
 
+func TestOrdinalDateBasicParsing(t *testing.T) {
+	assert := assert.New(t)
+
+	testDate, err := ParseOrdinalDate("2020001")
+	assert.NoError(err)
+	assert.True(testDate.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	testDate, err = ParseOrdinalDate("2020366")
+	assert.NoError(err)
+	assert.True(testDate.Equal(time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)))
+
+	// same range checks as the extended form
+	for _, s := range []string{"2021366", "2020000", "2020367"} {
+		_, err = ParseOrdinalDate(s)
+		assert.Error(err, s)
+	}
+
+	// an 8-digit basic calendar date isn't an ordinal date
+	_, err = ParseOrdinalDate("20200101")
+	assert.Error(err)
+	_, err = ParseOrdinalDate("202001")
+	assert.Error(err)
+	_, err = ParseOrdinalDate("2020-01")
+	assert.Error(err)
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	testDate, err = ParseOrdinalDateInLocation("2020032", tokyo)
+	assert.NoError(err)
+	assert.True(testDate.Equal(time.Date(2020, 2, 1, 0, 0, 0, 0, tokyo)))
+}
+
+func TestOrdinalDateParsingInLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(err)
+
+	local, err := ParseOrdinalDateInLocation("2020-032", tokyo)
+	assert.NoError(err)
+	assert.True(local.Equal(time.Date(2020, 2, 1, 0, 0, 0, 0, tokyo)))
+	assert.Equal(tokyo, local.Location())
+
+	// midnight in Tokyo is 9 hours before midnight UTC
+	utc, err := ParseOrdinalDate("2020-032")
+	assert.NoError(err)
+	assert.Equal(9*time.Hour, utc.Sub(local))
+
+	utc, err = ParseOrdinalDateInLocation("2020-032", nil)
+	assert.NoError(err)
+	assert.Equal(time.UTC, utc.Location())
+
+	_, err = ParseOrdinalDateInLocation("2021-366", tokyo)
+	assert.Error(err)
+}
+
 func TestISOWeekFormatting(t *testing.T) {
 	assert := assert.New(t)
 
@@ -49,6 +106,127 @@ func TestISOWeekFormatting(t *testing.T) {
 
 }
 
+func TestUSWeekNumber(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, tc := range []struct {
+		date             time.Time
+		usYear, usWeek   int
+		isoYear, isoWeek int
+	}{
+		// Friday; ISO week 1 starts the following Monday
+		{time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), 2021, 1, 2020, 53},
+		// Sunday starts a US week, but ends an ISO one
+		{time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC), 2021, 2, 2020, 53},
+		{time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC), 2021, 2, 2021, 1},
+		{time.Date(2021, 3, 7, 23, 0, 0, 0, time.UTC), 2021, 11, 2021, 9},
+		{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), 2017, 1, 2016, 52},
+		// Tuesday; ISO week 1 of the next year
+		{time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC), 2019, 53, 2020, 1},
+		// a leap year beginning on Saturday has 54 US weeks
+		{time.Date(2000, 12, 31, 0, 0, 0, 0, time.UTC), 2000, 54, 2000, 52},
+		// both agree here
+		{time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), 2020, 53, 2020, 53},
+	} {
+		year, week := USWeekNumber(tc.date)
+		assert.Equal(tc.usYear, year, tc.date.String())
+		assert.Equal(tc.usWeek, week, tc.date.String())
+
+		isoYear, isoWeek := tc.date.ISOWeek()
+		assert.Equal(tc.isoYear, isoYear, tc.date.String())
+		assert.Equal(tc.isoWeek, isoWeek, tc.date.String())
+	}
+}
+
+func TestWeekIsZero(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(Week{}.IsZero())
+	assert.False(Week{Year: 2021, Week: 3}.IsZero())
+	assert.False(Week{Year: 2021, Week: 3, Day: 1}.IsZero())
+	assert.False(Week{Day: 1}.IsZero())
+}
+
+func TestWeekString(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("2021-W03", Week{Year: 2021, Week: 3}.String())
+	assert.Equal("2021-W03-7", Week{Year: 2021, Week: 3, Day: 7}.String())
+	assert.Equal("0999-W52", Week{Year: 999, Week: 52}.String())
+	assert.Equal("+12021-W03-1", Week{Year: 12021, Week: 3, Day: 1}.String())
+	assert.Equal("+123456-W03", Week{Year: 123456, Week: 3}.String())
+}
+
+func TestFormatWeekRange(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := FormatWeekRange(Week{Year: 2021, Week: 1}, Week{Year: 2021, Week: 4})
+	assert.NoError(err)
+	assert.Equal("2021-W01/2021-W04", s)
+
+	s, err = FormatWeekRange(Week{Year: 2020, Week: 53, Day: 5}, Week{Year: 2021, Week: 1, Day: 1})
+	assert.NoError(err)
+	assert.Equal("2020-W53-5/2021-W01-1", s)
+
+	// a single week
+	s, err = FormatWeekRange(Week{Year: 2021, Week: 1}, Week{Year: 2021, Week: 1})
+	assert.NoError(err)
+	assert.Equal("2021-W01/2021-W01", s)
+
+	_, err = FormatWeekRange(Week{Year: 2021, Week: 53}, Week{Year: 2022, Week: 1})
+	assert.Equal(ErrWeekRange, err)
+	_, err = FormatWeekRange(Week{Year: 2021, Week: 1}, Week{Year: 2021, Week: 2, Day: 8})
+	assert.Equal(ErrWeekdayRange, err)
+	_, err = FormatWeekRange(Week{}, Week{Year: 2021, Week: 1})
+	assert.Equal(ErrYearRange, err)
+	_, err = FormatWeekRange(Week{Year: 2021, Week: 4}, Week{Year: 2021, Week: 1})
+	assert.Error(err)
+}
+
+func TestParseWeekRange(t *testing.T) {
+	assert := assert.New(t)
+
+	start, end, err := ParseWeekRange("2021-W01/2021-W04")
+	assert.NoError(err)
+	assert.Equal(Week{Year: 2021, Week: 1}, start)
+	assert.Equal(Week{Year: 2021, Week: 4}, end)
+
+	start, end, err = ParseWeekRange("2020-W53-5/2021W011")
+	assert.NoError(err)
+	assert.Equal(Week{Year: 2020, Week: 53, Day: 5}, start)
+	assert.Equal(Week{Year: 2021, Week: 1, Day: 1}, end)
+
+	// round trip
+	s, err := FormatWeekRange(start, end)
+	assert.NoError(err)
+	start2, end2, err := ParseWeekRange(s)
+	assert.NoError(err)
+	assert.Equal(start, start2)
+	assert.Equal(end, end2)
+
+	_, _, err = ParseWeekRange("2021-W01")
+	assert.Error(err)
+	_, _, err = ParseWeekRange("2021-W04/2021-W01")
+	assert.Error(err)
+	_, _, err = ParseWeekRange("2021-W01/2021-W53")
+	assert.Equal(ErrWeekRange, err)
+
+	// expanded years round trip
+	start, end, err = ParseWeekRange("+012021-W01/+012021-W04-7")
+	assert.NoError(err)
+	assert.Equal(Week{Year: 12021, Week: 1}, start)
+	assert.Equal(Week{Year: 12021, Week: 4, Day: 7}, end)
+	s, err = FormatWeekRange(start, end)
+	assert.NoError(err)
+	assert.Equal("+12021-W01/+12021-W04-7", s)
+	start2, end2, err = ParseWeekRange(s)
+	assert.NoError(err)
+	assert.Equal(start, start2)
+	assert.Equal(end, end2)
+	_, err = ParseWeek(start.String())
+	assert.NoError(err)
+}
+
 func TestISOWeekParsing(t *testing.T) {
 	assert := assert.New(t)
 
@@ -67,6 +245,31 @@ func TestISOWeekParsing(t *testing.T) {
 	assert.Error(err)
 }
 
+func TestISOWeekExpandedYear(t *testing.T) {
+	assert := assert.New(t)
+
+	testDate, err := ParseWeek("+012021-W03")
+	assert.NoError(err)
+	year, week := testDate.ISOWeek()
+	assert.Equal(12021, year)
+	assert.Equal(3, week)
+	assert.Equal(time.Monday, testDate.Weekday())
+
+	testDate, err = ParseWeek("+02021W035")
+	assert.NoError(err)
+	assert.True(testDate.Equal(time.Date(2021, 1, 22, 0, 0, 0, 0, time.UTC)))
+
+	// years before year 1 are still out of range
+	_, err = ParseWeek("-002021-W03")
+	assert.Equal(ErrYearRange, err)
+
+	// expanded years need both the sign and the extra digits
+	_, err = ParseWeek("+2021-W03")
+	assert.Error(err)
+	_, err = ParseWeek("012021-W03")
+	assert.Error(err)
+}
+
 func TestISODurationFormatting(t *testing.T) {
 	assert := assert.New(t)
 
@@ -79,6 +282,25 @@ func TestISODurationFormatting(t *testing.T) {
 	assert.Equal(FormatDuration(t2.Sub(t1)), "PT1H0M0S")
 }
 
+func TestISODurationNegativeFractionFormatting(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("-PT1.5S", FormatDuration(-1500*time.Millisecond))
+	assert.Equal("-PT0.5S", FormatDuration(-500*time.Millisecond))
+	assert.Equal("-PT1M0.025S", FormatDuration(-(time.Minute + 25*time.Millisecond)))
+	assert.Equal("-PT1.5S", FormatDurationExtended(-1500*time.Millisecond))
+	assert.Equal("-PT00H00M01.5S", FormatDurationPadded(-1500*time.Millisecond))
+	assert.Equal("-PT0.000000001S", FormatDurationWith(-time.Nanosecond, 0, Truncate))
+
+	// truncation is toward zero, so nothing is left of a negative sub-millisecond
+	assert.Equal("PT0S", FormatDuration(-time.Microsecond))
+
+	// and they parse back
+	dur, err := ParseDuration(FormatDuration(-1500 * time.Millisecond))
+	assert.NoError(err)
+	assert.Equal(-1500*time.Millisecond, dur)
+}
+
 func TestISODurationParsing(t *testing.T) {
 	assert := assert.New(t)
 
@@ -96,3 +318,980 @@ func TestISODurationParsing(t *testing.T) {
 	_, err = ParseDuration("I-LOVE-CATS")
 	assert.Error(err)
 }
+
+func TestISODurationAltParsing(t *testing.T) {
+	assert := assert.New(t)
+
+	// years and months are skipped, same as the designator format
+	dur, err := ParseDuration("P0003-06-04T12:30:05")
+	assert.NoError(err)
+	assert.Equal(4*24*time.Hour+12*time.Hour+30*time.Minute+5*time.Second, dur)
+
+	// fractional seconds behave the same as PT5.5S
+	dur, err = ParseDuration("P0000-00-00T00:00:05.5")
+	assert.NoError(err)
+	expected, err := ParseDuration("PT5.5S")
+	assert.NoError(err)
+	assert.Equal(expected, dur)
+	assert.Equal(5500*time.Millisecond, dur)
+
+	// the alternative format needs every field
+	_, err = ParseDuration("P0000-00-00T00:05")
+	assert.Error(err)
+}
+
+func TestISODurationAltMalformed(t *testing.T) {
+	assert := assert.New(t)
+
+	lenient := Parser{Lenient: true}
+	for _, s := range []string{
+		// the alternative format has no weeks
+		"P0003-W06-04T12:30:05",
+		"P0003-06-04WT12:30:05",
+		"P0003-06-01W",
+		"P0001W-06-04T12:30:05",
+		// and can't be mixed with designators
+		"P0003-06-04T1H",
+		"P0003-06-04T12:30:05S",
+		"P3Y-06-04T12:30:05",
+		"P0003-06-04T12H30M5S",
+		"P0003-06-04DT12:30:05",
+		"P0003-06DT12:30:05",
+	} {
+		_, err := ParseDuration(s)
+		assert.Error(err, s)
+		_, err = ParseISODuration(s)
+		assert.Error(err, s)
+		_, err = lenient.ParseDuration(s)
+		assert.Error(err, s)
+		assert.False(IsValidDuration(s), s)
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IsValidDuration("P1DT1H"))
+	assert.True(IsValidDuration("P0003-06-04T12:30:05"))
+	assert.False(IsValidDuration("P1H"))
+	assert.False(IsValidDuration("I-LOVE-CATS"))
+
+	assert.True(IsValidWeek("1999-W52-6"))
+	assert.True(IsValidWeek("2020-W53"))
+	assert.False(IsValidWeek("2021-W53")) // 2021 only has 52 weeks
+	assert.False(IsValidWeek("2021-W03-8"))
+	assert.False(IsValidWeek("0000-W01"))
+
+	assert.True(IsValidOrdinalDate("2020-366"))
+	assert.False(IsValidOrdinalDate("2020-367"))
+	assert.False(IsValidOrdinalDate("2020-01-01"))
+}
+
+func TestValidateDurations(t *testing.T) {
+	assert := assert.New(t)
+
+	errs := ValidateDurations([]string{"PT1H", "P", "P1DT2H", "1H", "P1W1D", "-PT0.5S", ""})
+	assert.Len(errs, 7)
+	for i, invalid := range []bool{false, true, false, true, true, false, true} {
+		if invalid {
+			assert.Error(errs[i], i)
+		} else {
+			assert.NoError(errs[i], i)
+		}
+	}
+
+	assert.Empty(ValidateDurations(nil))
+}
+
+func TestISODurationRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	durations := []time.Duration{
+		0,
+		time.Nanosecond,
+		time.Millisecond - 1,
+		time.Millisecond,
+		500 * time.Millisecond,
+		time.Second - time.Millisecond,
+		time.Second,
+		time.Second + time.Millisecond,
+		time.Minute - time.Millisecond,
+		time.Hour + time.Second,
+		24*time.Hour - time.Millisecond,
+		16777217 * time.Second, // not representable as a float32
+	}
+	r := rand.New(rand.NewSource(8601))
+	for i := 0; i < 1000; i++ {
+		durations = append(durations, time.Duration(r.Int63n(int64(10000*time.Hour))))
+	}
+
+	for _, d := range durations {
+		formatted := FormatDuration(d)
+		parsed, err := ParseDuration(formatted)
+		assert.NoError(err, formatted)
+		assert.Equal(d.Truncate(time.Millisecond), parsed, formatted)
+	}
+
+	// the extremes, both as FormatDuration emits them and to the nanosecond
+	for _, d := range []time.Duration{math.MaxInt64, math.MinInt64, math.MinInt64 + 1} {
+		formatted := FormatDuration(d)
+		parsed, err := ParseDuration(formatted)
+		assert.NoError(err, formatted)
+		assert.Equal(d.Truncate(time.Millisecond), parsed, formatted)
+
+		formatted = FormatDurationWith(d, 0, Truncate)
+		parsed, err = ParseDuration(formatted)
+		assert.NoError(err, formatted)
+		assert.Equal(d, parsed, formatted)
+	}
+	assert.Equal("PT2562047H47M16.854775807S", FormatDurationWith(math.MaxInt64, 0, Truncate))
+
+	// one past either extreme is out of range
+	_, err := ParseDuration("PT2562047H47M16.854775808S")
+	assert.Equal(ErrDurationRange, err)
+	_, err = ParseDuration("-PT2562047H47M16.854775809S")
+	assert.Equal(ErrDurationRange, err)
+
+	// sub-second durations are expressed in fractional seconds
+	assert.Equal("PT0.5S", FormatDuration(500*time.Millisecond))
+	assert.Equal("PT1S", FormatDuration(time.Second))
+	assert.Equal("PT0S", FormatDuration(0))
+}
+
+func TestWeekToOrdinal(t *testing.T) {
+	assert := assert.New(t)
+
+	// 1999-W52-6 lands in the next calendar year
+	year, day, err := WeekToOrdinal(1999, 52, 6)
+	assert.NoError(err)
+	assert.Equal(2000, year)
+	assert.Equal(1, day)
+
+	// 2021-W01-1 is Jan 4, 2021
+	year, day, err = WeekToOrdinal(2021, 1, 1)
+	assert.NoError(err)
+	assert.Equal(2021, year)
+	assert.Equal(4, day)
+
+	// 2020-W01-1 is Dec 30, 2019
+	year, day, err = WeekToOrdinal(2020, 1, 1)
+	assert.NoError(err)
+	assert.Equal(2019, year)
+	assert.Equal(364, day)
+
+	_, _, err = WeekToOrdinal(2021, 53, 1)
+	assert.Equal(ErrWeekRange, err)
+	_, _, err = WeekToOrdinal(2021, 3, 8)
+	assert.Equal(ErrWeekdayRange, err)
+	_, _, err = WeekToOrdinal(0, 3, 1)
+	assert.Equal(ErrYearRange, err)
+}
+
+func TestIsCanonicalWeek(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IsCanonicalWeek("2021-W03"))
+	assert.True(IsCanonicalWeek("2021-W03-1"))
+	assert.False(IsCanonicalWeek("2021-W3"))
+	assert.False(IsCanonicalWeek("2021-W03-01"))
+	assert.False(IsCanonicalWeek("2021W03"))
+	assert.False(IsCanonicalWeek("2021-W53"))
+}
+
+func TestISODurationExplicitZeros(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		iso      string
+		expected time.Duration
+	}{
+		{"P0Y0M1D", 24 * time.Hour},
+		{"PT0H0M5S", 5 * time.Second},
+		{"P0D", 0},
+		{"P0Y0M0DT0H0M0S", 0},
+		{"P0Y0M0DT0H1M0S", time.Minute},
+		{"P1Y0M0DT0H0M0S", 0}, // years are ignored
+		{"P0Y1M0DT1H0M0S", time.Hour},
+		{"P0Y0M2DT0H0M0.5S", 48*time.Hour + 500*time.Millisecond},
+	}
+
+	for _, test := range tests {
+		dur, err := ParseDuration(test.iso)
+		assert.NoError(err, test.iso)
+		assert.Equal(test.expected, dur, test.iso)
+	}
+
+	// ...but there has to be something there
+	_, err := ParseDuration("P")
+	assert.Error(err)
+}
+
+func TestISODurationZeroVersusEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	// P says nothing at all, so it's invalid
+	_, err := ParseDuration("P")
+	assert.Error(err)
+	_, err = ParseISODuration("P")
+	assert.Error(err)
+
+	// whereas P0D and PT0S are explicit zeros
+	for _, s := range []string{"P0D", "PT0S", "-P0D", "-PT0S"} {
+		dur, err := ParseDuration(s)
+		assert.NoError(err, s)
+		assert.Equal(time.Duration(0), dur, s)
+
+		d, err := ParseISODuration(s)
+		assert.NoError(err, s)
+		assert.Equal("PT0S", d.Abs().String(), s)
+	}
+}
+
+func TestParseZoned(t *testing.T) {
+	assert := assert.New(t)
+
+	noon := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		iso      string
+		expected time.Time
+	}{
+		{"2020-01-01T12:00:00Z", noon},
+		{"2020-01-01T13:00:00+01", noon},
+		{"2020-01-01T13:00:00+0100", noon},
+		{"2020-01-01T13:00:00+01:00", noon},
+		{"2020-01-01T06:30:00-0530", noon},
+		{"2020-01-01T06:30:00-05:30", noon},
+		{"2020-01-01T12:00:00.25Z", noon.Add(250 * time.Millisecond)},
+		{"2020-01-01T13:00:00.25+01:00", noon.Add(250 * time.Millisecond)},
+	}
+
+	for _, test := range tests {
+		parsed, err := ParseZoned(test.iso)
+		assert.NoError(err, test.iso)
+		assert.True(test.expected.Equal(parsed), test.iso)
+	}
+
+	// the zone is required
+	_, err := ParseZoned("2020-01-01T12:00:00")
+	assert.Error(err)
+	_, err = ParseZoned("2020-01-01T12:00:00+1")
+	assert.Error(err)
+}
+
+func TestFormatWeekParts(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := FormatWeekParts(2021, 3, 0, true)
+	assert.NoError(err)
+	assert.Equal("2021-W03", s)
+
+	s, err = FormatWeekParts(2021, 3, 1, false)
+	assert.NoError(err)
+	assert.Equal("2021-W03-1", s)
+
+	_, err = FormatWeekParts(2021, 53, 1, false)
+	assert.Equal(ErrWeekRange, err)
+	_, err = FormatWeekParts(10000, 1, 1, false)
+	assert.Equal(ErrYearRange, err)
+	_, err = FormatWeekParts(2021, 3, 0, false)
+	assert.Equal(ErrWeekdayRange, err)
+}
+
+func TestParseZonedDecimalComma(t *testing.T) {
+	assert := assert.New(t)
+
+	parsed, err := ParseZoned("2020-01-01T12:00:00,5Z")
+	assert.NoError(err)
+	assert.True(parsed.Equal(time.Date(2020, 1, 1, 12, 0, 0, 500000000, time.UTC)))
+
+	parsed, err = Parser{}.ParseDateTime("2020-01-01T12:00:00,25")
+	assert.NoError(err)
+	assert.True(parsed.Equal(time.Date(2020, 1, 1, 12, 0, 0, 250000000, time.UTC)))
+
+	// only the decimal sign may be a comma
+	assert.Equal("2020-01-01T12:00:00.5Z", normalizeFraction("2020-01-01T12:00:00,5Z"))
+	assert.Equal("2020,01-01T12:00:00Z", normalizeFraction("2020,01-01T12:00:00Z"))
+	_, err = ParseZoned("2020,01-01T12:00:00Z")
+	assert.Error(err)
+}
+
+func TestISOWeekBasic(t *testing.T) {
+	assert := assert.New(t)
+
+	// week only, 7 characters
+	testDate, err := ParseWeek("2021W03")
+	assert.NoError(err)
+	assert.True(testDate.Equal(time.Date(2021, 1, 18, 0, 0, 0, 0, time.UTC)))
+
+	// week and day, 8 characters
+	testDate, err = ParseWeek("2021W031")
+	assert.NoError(err)
+	assert.True(testDate.Equal(time.Date(2021, 1, 18, 0, 0, 0, 0, time.UTC)))
+	testDate, err = ParseWeek("1999W526")
+	assert.NoError(err)
+	assert.True(testDate.Equal(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	// no mixing basic and extended
+	_, err = ParseWeek("2021-W031")
+	assert.Error(err)
+	_, err = ParseWeek("2021W03-1")
+	assert.Error(err)
+	_, err = ParseWeek("2021W0311")
+	assert.Error(err)
+
+	assert.Equal("2021W03", FormatWeekBasic(time.Date(2021, 1, 18, 0, 0, 0, 0, time.UTC), true))
+	assert.Equal("1999W526", FormatWeekBasic(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), false))
+}
+
+func TestISOWeekBasicRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, d := range []time.Time{
+		time.Date(2021, 1, 18, 0, 0, 0, 0, time.UTC),
+		// early January in the previous ISO year, late December in the next
+		time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, 12, 30, 0, 0, 0, 0, time.UTC),
+		// week 53
+		time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC),
+		time.Date(1999, 7, 4, 0, 0, 0, 0, time.UTC),
+	} {
+		s := FormatWeekBasic(d, false)
+		assert.Len(s, 8, s)
+
+		parsed, err := ParseWeek(s)
+		assert.NoError(err, s)
+		assert.True(parsed.Equal(d), s)
+		assert.Equal(s, FormatWeekBasic(parsed, false))
+	}
+}
+
+func TestISODurationFractions(t *testing.T) {
+	assert := assert.New(t)
+
+	// minutes are the last component present
+	dur, err := ParseDuration("PT1.5M")
+	assert.NoError(err)
+	assert.Equal(90*time.Second, dur)
+
+	dur, err = ParseDuration("PT1H1.5M")
+	assert.NoError(err)
+	assert.Equal(time.Hour+90*time.Second, dur)
+
+	dur, err = ParseDuration("PT0.25H")
+	assert.NoError(err)
+	assert.Equal(15*time.Minute, dur)
+
+	// but anywhere else a fraction is invalid
+	_, err = ParseDuration("PT1.5M30S")
+	assert.Error(err)
+	_, err = ParseDuration("PT1.5H1M")
+	assert.Error(err)
+}
+
+func TestISODurationFractionalDays(t *testing.T) {
+	assert := assert.New(t)
+
+	dur, err := ParseDuration("P0.5D")
+	assert.NoError(err)
+	assert.Equal(12*time.Hour, dur)
+
+	dur, err = ParseDuration("P1.5D")
+	assert.NoError(err)
+	assert.Equal(36*time.Hour, dur)
+
+	dur, err = ParseDuration("-P1.5D")
+	assert.NoError(err)
+	assert.Equal(-36*time.Hour, dur)
+
+	// the struct keeps the fraction, and resolves it the same way
+	d, err := ParseISODuration("P1.5D")
+	assert.NoError(err)
+	assert.Equal(Duration{Days: 1.5}, d)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(d.AddTo(start).Equal(start.Add(36 * time.Hour)))
+
+	// a fractional day is still only allowed last
+	_, err = ParseDuration("P1.5DT1H")
+	assert.Error(err)
+}
+
+func TestISODurationLeadingDot(t *testing.T) {
+	assert := assert.New(t)
+
+	dur, err := ParseDuration("PT.5S")
+	assert.NoError(err)
+	assert.Equal(500*time.Millisecond, dur)
+
+	dur, err = ParseDuration("-PT1M.25S")
+	assert.NoError(err)
+	assert.Equal(-(time.Minute + 250*time.Millisecond), dur)
+
+	d, err := ParseISODuration("P.5D")
+	assert.NoError(err)
+	assert.Equal(Duration{Days: 0.5}, d)
+
+	// there must be digits after the dot, and nothing else in its place
+	_, err = ParseDuration("PT.S")
+	assert.Error(err)
+	_, err = ParseDuration("PT5.S")
+	assert.Error(err)
+	_, err = ParseDuration("PT1x5S")
+	assert.Error(err)
+	// the leading dot is still a fraction, so it must come last
+	_, err = ParseDuration("PT.5M1S")
+	assert.Error(err)
+}
+
+func TestISODurationTrailingT(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ParseDuration("P1DT")
+	assert.EqualError(err, "duration string has a T but no time components")
+	_, err = ParseDuration("PT")
+	assert.EqualError(err, "duration string has a T but no time components")
+	_, err = ParseISODuration("P1DT")
+	assert.Error(err)
+
+	dur, err := ParseDuration("P1DT1H")
+	assert.NoError(err)
+	assert.Equal(25*time.Hour, dur)
+}
+
+func TestISOWeekYearStart(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(ISOWeekYearStart(2021).Equal(time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)))
+	assert.True(ISOWeekYearStart(2015).Equal(time.Date(2014, 12, 29, 0, 0, 0, 0, time.UTC)))
+	assert.True(ISOWeekYearStart(2020).Equal(time.Date(2019, 12, 30, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestWeekStartsInYear(t *testing.T) {
+	assert := assert.New(t)
+
+	mondays := WeekStartsInYear(2021)
+	assert.Len(mondays, 52)
+	assert.True(mondays[0].Equal(time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)))
+	assert.True(mondays[51].Equal(time.Date(2021, 12, 27, 0, 0, 0, 0, time.UTC)))
+
+	mondays = WeekStartsInYear(2020)
+	assert.Len(mondays, 53)
+	assert.True(mondays[0].Equal(time.Date(2019, 12, 30, 0, 0, 0, 0, time.UTC)))
+	assert.Equal("2020-W53-1", FormatWeek(mondays[52], false))
+}
+
+func TestFormatRFC3339UTCOffset(t *testing.T) {
+	assert := assert.New(t)
+
+	utc := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal("2020-01-01T12:00:00Z", FormatDateTime(utc, time.RFC3339))
+	assert.Equal("2020-01-01T12:00:00+00:00", FormatRFC3339UTCOffset(utc))
+
+	assert.Equal("2020-01-01T12:00:00.5+00:00", FormatRFC3339UTCOffset(utc.Add(500*time.Millisecond)))
+	assert.Equal("2020-01-01T07:00:00-05:00", FormatRFC3339UTCOffset(utc.In(time.FixedZone("EST", -5*60*60))))
+}
+
+func TestISOWeekRangeErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	// impossible weeks fail the format
+	_, err := ParseWeek("2021-W59")
+	assert.EqualError(err, "isoWeek string is of incorrect format")
+	_, err = ParseWeek("2021-W60")
+	assert.EqualError(err, "isoWeek string is of incorrect format")
+	_, err = ParseWeek("2021-W54")
+	assert.EqualError(err, "isoWeek string is of incorrect format")
+
+	// weeks that are possible in some years fail the range check
+	_, err = ParseWeek("2021-W53")
+	assert.Equal(ErrWeekRange, err)
+	_, err = ParseWeek("2021-W00")
+	assert.Equal(ErrWeekRange, err)
+
+	_, err = ParseWeek("2020-W53")
+	assert.NoError(err)
+}
+
+func TestISODurationRepeatedUnits(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, s := range []string{"P1D1D", "PT1H1H", "P1M1M", "PT1M1M", "P1Y1Y", "PT1S1S", "P1DT1H1D"} {
+		_, err := ParseDuration(s)
+		assert.Error(err, s)
+		_, err = ParseISODuration(s)
+		assert.Error(err, s)
+	}
+
+	// months and minutes share a designator, but not a position
+	dur, err := ParseDuration("P1MT1M")
+	assert.NoError(err)
+	assert.Equal(time.Minute, dur)
+}
+
+func TestISODurationDecimalPoint(t *testing.T) {
+	assert := assert.New(t)
+
+	dur, err := ParseDuration("PT1.5S")
+	assert.NoError(err)
+	assert.Equal(1500*time.Millisecond, dur)
+
+	// only a real decimal point separates the fraction
+	for _, s := range []string{"PT1X5S", "PT1x5S", "PT1_5S", "PT1X5M", "P1X5D", "P1X5W", "P0000-00-00T00:00:01X5"} {
+		_, err := ParseDuration(s)
+		assert.Error(err, s)
+		_, err = ParseISODuration(s)
+		assert.Error(err, s)
+	}
+}
+
+func TestISODurationPaddedFormatting(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("PT01H05M09S", FormatDurationPadded(time.Hour+5*time.Minute+9*time.Second))
+	assert.Equal("PT00H00M09S", FormatDurationPadded(9*time.Second))
+	assert.Equal("PT00H00M09.25S", FormatDurationPadded(9250*time.Millisecond))
+	assert.Equal("P02DT01H00M00S", FormatDurationPadded(49*time.Hour))
+	assert.Equal("-PT00H01M00S", FormatDurationPadded(-time.Minute))
+
+	// still parseable
+	dur, err := ParseDuration(FormatDurationPadded(49*time.Hour + 9250*time.Millisecond))
+	assert.NoError(err)
+	assert.Equal(49*time.Hour+9250*time.Millisecond, dur)
+}
+
+func TestIsLongISOYear(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IsLongISOYear(2015))
+	assert.True(IsLongISOYear(2020))
+	assert.True(IsLongISOYear(2026))
+	assert.False(IsLongISOYear(2021))
+	assert.False(IsLongISOYear(2019))
+}
+
+func TestTimeParsing(t *testing.T) {
+	assert := assert.New(t)
+
+	expected := time.Date(0, 1, 1, 15, 30, 0, 0, time.UTC)
+
+	parsed, err := ParseTime("15:30")
+	assert.NoError(err)
+	assert.True(parsed.Equal(expected))
+
+	// leading T is optional
+	parsed, err = ParseTime("T15:30")
+	assert.NoError(err)
+	assert.True(parsed.Equal(expected))
+
+	parsed, err = ParseTime("T15:30:45.5")
+	assert.NoError(err)
+	assert.True(parsed.Equal(expected.Add(45500 * time.Millisecond)))
+
+	parsed, err = ParseTime("T16:30:00+01:00")
+	assert.NoError(err)
+	assert.True(parsed.Equal(expected))
+
+	_, err = ParseTime("T")
+	assert.Error(err)
+	_, err = ParseTime("TT15:30")
+	assert.Error(err)
+	_, err = ParseTime("25:00")
+	assert.Error(err)
+}
+
+func TestReducedTimeParsing(t *testing.T) {
+	assert := assert.New(t)
+
+	parsed, err := ParseTime("15")
+	assert.NoError(err)
+	assert.True(parsed.Equal(time.Date(0, 1, 1, 15, 0, 0, 0, time.UTC)))
+
+	parsed, err = ParseTime("15:30")
+	assert.NoError(err)
+	assert.True(parsed.Equal(time.Date(0, 1, 1, 15, 30, 0, 0, time.UTC)))
+
+	parsed, err = ParseTime("15:30:45")
+	assert.NoError(err)
+	assert.True(parsed.Equal(time.Date(0, 1, 1, 15, 30, 45, 0, time.UTC)))
+
+	parsed, err = ParseTime("T16+01:00")
+	assert.NoError(err)
+	assert.True(parsed.Equal(time.Date(0, 1, 1, 15, 0, 0, 0, time.UTC)))
+
+	// 24 is the end of the day
+	for _, s := range []string{"24", "24:00", "T24:00:00", "24:00:00.000", "24:00Z"} {
+		parsed, err = ParseTime(s)
+		assert.NoError(err, s)
+		assert.True(parsed.Equal(time.Date(0, 1, 2, 0, 0, 0, 0, time.UTC)), s)
+	}
+	for _, s := range []string{"24:01", "24:00:01", "24:00:00.5", "25"} {
+		_, err = ParseTime(s)
+		assert.Error(err, s)
+	}
+
+	// hours always have two digits
+	for _, s := range []string{"2", "T9", "9Z", "9+01:00", "9:30", "9:30:45", ""} {
+		_, err = ParseTime(s)
+		assert.Error(err, s)
+	}
+}
+
+func TestISODurationRoundModes(t *testing.T) {
+	assert := assert.New(t)
+
+	dur := 1600 * time.Millisecond
+	assert.Equal("PT1S", FormatDurationWith(dur, time.Second, Truncate))
+	assert.Equal("PT2S", FormatDurationWith(dur, time.Second, Round))
+	assert.Equal("PT2S", FormatDurationWith(dur, time.Second, Ceil))
+
+	// Ceil never under-reports
+	dur = 1200 * time.Millisecond
+	assert.Equal("PT1S", FormatDurationWith(dur, time.Second, Round))
+	assert.Equal("PT2S", FormatDurationWith(dur, time.Second, Ceil))
+	assert.Equal("-PT2S", FormatDurationWith(-dur, time.Second, Ceil))
+	assert.Equal("PT1S", FormatDurationWith(time.Second, time.Second, Ceil))
+
+	assert.Equal("PT1M0S", FormatDurationWith(59*time.Second, time.Minute, Ceil))
+	assert.Equal("PT1.2S", FormatDurationWith(dur, 0, Truncate))
+	assert.Equal("PT0.000000001S", FormatDurationWith(time.Nanosecond, time.Nanosecond, Round))
+
+	// Ceil saturates rather than overflowing into the opposite sign
+	longest, shortest := time.Duration(math.MaxInt64), time.Duration(math.MinInt64)
+	assert.Equal(formatDuration(longest), FormatDurationWith(longest, time.Second, Ceil))
+	assert.Equal(formatDuration(longest), FormatDurationWith(longest-1, time.Hour, Ceil))
+	assert.Equal(formatDuration(shortest), FormatDurationWith(shortest+1, time.Second, Ceil))
+	assert.Equal(formatDuration(shortest), FormatDurationWith(shortest, time.Second, Ceil))
+	assert.Equal("PT2562047H47M16.854775807S", FormatDurationWith(longest, time.Second, Ceil))
+	assert.Equal("-PT2562047H47M16.854775808S", FormatDurationWith(shortest+1, time.Second, Ceil))
+	// Round already saturates
+	assert.Equal(formatDuration(longest), FormatDurationWith(longest, time.Second, Round))
+}
+
+func TestValidateWeekString(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, s := range []string{"2021-W03-1", "2021W037", "2020-W53", "+012021-W03-5", "1999-W52-6"} {
+		assert.NoError(ValidateWeekString(s), s)
+	}
+
+	// broken strings are reported, not silently resolved
+	assert.Error(ValidateWeekString("2021-W03-8"))
+	assert.Equal(ErrWeekRange, ValidateWeekString("2021-W53-1"))
+	assert.Error(ValidateWeekString("2021-W03-1x"))
+	assert.Error(ValidateWeekString("2021-W031"))
+}
+
+func TestWeekBoundsOrdinal(t *testing.T) {
+	assert := assert.New(t)
+
+	start, end, err := WeekBoundsOrdinal(2021, 3)
+	assert.NoError(err)
+	assert.Equal("2021-018", start)
+	assert.Equal("2021-024", end)
+
+	// across New Year
+	start, end, err = WeekBoundsOrdinal(2020, 53)
+	assert.NoError(err)
+	assert.Equal("2020-363", start)
+	assert.Equal("2021-003", end)
+
+	start, end, err = WeekBoundsOrdinal(2015, 1)
+	assert.NoError(err)
+	assert.Equal("2014-363", start)
+	assert.Equal("2015-004", end)
+
+	_, _, err = WeekBoundsOrdinal(2021, 53)
+	assert.Equal(ErrWeekRange, err)
+	_, _, err = WeekBoundsOrdinal(0, 1)
+	assert.Equal(ErrYearRange, err)
+}
+
+func TestWeekStringToOrdinalString(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := WeekStringToOrdinalString("1999-W52-6")
+	assert.NoError(err)
+	assert.Equal("2000-001", s)
+
+	s, err = WeekStringToOrdinalString("2021W03")
+	assert.NoError(err)
+	assert.Equal("2021-018", s)
+
+	_, err = WeekStringToOrdinalString("2021-W53")
+	assert.Equal(ErrWeekRange, err)
+}
+
+func TestCurrentISOWeek(t *testing.T) {
+	assert := assert.New(t)
+
+	// late on Sunday, Jan 3, 2021 in UTC, which is already Monday in Tokyo
+	defer fixNow(time.Date(2021, 1, 3, 20, 0, 0, 0, time.UTC))()
+
+	assert.Equal("2020-W53-7", CurrentISOWeek(time.UTC, false))
+	assert.Equal("2020-W53", CurrentISOWeek(nil, true))
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	assert.Equal("2021-W01-1", CurrentISOWeek(tokyo, false))
+}
+
+func TestNowUTC(t *testing.T) {
+	assert := assert.New(t)
+
+	est := time.FixedZone("EST", -5*60*60)
+	restore := fixNow(time.Date(2020, 1, 1, 7, 0, 0, 123456789, est))
+	assert.Equal("2020-01-01T12:00:00.123456789Z", NowUTC())
+	restore()
+
+	defer fixNow(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC))()
+	assert.Equal("2020-01-01T12:00:00Z", NowUTC())
+}
+
+// fixNow makes the package's clock always return t, and returns a function that restores it.
+func fixNow(t time.Time) (restore func()) {
+	saved := now
+	now = func() time.Time { return t }
+	return func() { now = saved }
+}
+
+func TestFractionalDigitLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	// a tenth fractional digit is below nanosecond resolution, so it's truncated
+	dur, err := ParseDuration("PT0.1234567891S")
+	assert.NoError(err)
+	assert.Equal(123456789*time.Nanosecond, dur)
+
+	dur, err = ParseDuration("PT0.9999999999S")
+	assert.NoError(err)
+	assert.Equal(999999999*time.Nanosecond, dur)
+
+	parsed, err := ParseZoned("2020-01-01T00:00:00.1234567891Z")
+	assert.NoError(err)
+	assert.Equal(123456789, parsed.Nanosecond())
+
+	parsed, err = ParseZoned("2020-01-01T00:00:00,9999999999Z")
+	assert.NoError(err)
+	assert.Equal(999999999, parsed.Nanosecond())
+	assert.Equal(0, parsed.Second())
+
+	parsed, err = ParseDateTimeInLocation("2020-01-01T00:00:00.1234567891", time.UTC)
+	assert.NoError(err)
+	assert.Equal(123456789, parsed.Nanosecond())
+}
+
+func TestISODurationWeeks(t *testing.T) {
+	assert := assert.New(t)
+
+	dur, err := ParseDuration("P2W")
+	assert.NoError(err)
+	assert.Equal(14*24*time.Hour, dur)
+
+	// weeks stand alone in strict ISO 8601
+	_, err = ParseDuration("P1W3DT2H")
+	assert.Error(err)
+
+	dur, err = Parser{Lenient: true}.ParseDuration("P1W3DT2H")
+	assert.NoError(err)
+	assert.Equal(10*24*time.Hour+2*time.Hour, dur)
+}
+
+func TestISODurationNegativeWeeks(t *testing.T) {
+	assert := assert.New(t)
+
+	dur, err := ParseDuration("-P2W")
+	assert.NoError(err)
+	assert.Equal(-14*24*time.Hour, dur)
+
+	d, err := ParseISODuration("-P2W")
+	assert.NoError(err)
+	assert.Equal(Duration{Negative: true, Weeks: 2}, d)
+
+	// the sign doesn't lift the week rule, nor the empty check
+	_, err = ParseDuration("-P1W1D")
+	assert.Error(err)
+	_, err = ParseDuration("-P")
+	assert.Error(err)
+	_, err = ParseDuration("--P2W")
+	assert.Error(err)
+}
+
+func TestISODurationExtendedFormatting(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("P1W3DT2H", FormatDurationExtended(10*24*time.Hour+2*time.Hour))
+	assert.Equal("P2W", FormatDurationExtended(14*24*time.Hour))
+	assert.Equal("P3D", FormatDurationExtended(3*24*time.Hour))
+	assert.Equal("PT1M1.5S", FormatDurationExtended(61500*time.Millisecond))
+	assert.Equal("PT0S", FormatDurationExtended(0))
+	assert.Equal("-P1WT1H", FormatDurationExtended(-(7*24*time.Hour + time.Hour)))
+
+	// lenient parsing reads it back
+	dur := 17*24*time.Hour + 5*time.Hour + 30*time.Second
+	parsed, err := Parser{Lenient: true}.ParseDuration(FormatDurationExtended(dur))
+	assert.NoError(err)
+	assert.Equal(dur, parsed)
+}
+
+func TestQuarter(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(1, Quarter(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(1, Quarter(time.Date(2020, 3, 31, 23, 59, 59, 0, time.UTC)))
+	assert.Equal(2, Quarter(time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(3, Quarter(time.Date(2020, 9, 30, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(4, Quarter(time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)))
+
+	assert.Equal("2020-Q1", FormatYearQuarter(time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC)))
+	assert.Equal("2020-Q2", FormatYearQuarter(time.Date(2020, 4, 15, 0, 0, 0, 0, time.UTC)))
+	assert.Equal("0999-Q4", FormatYearQuarter(time.Date(999, 11, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestParseYearQuarter(t *testing.T) {
+	assert := assert.New(t)
+
+	iv, err := ParseYearQuarter("2020-Q1")
+	assert.NoError(err)
+	assert.True(iv.Start.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(iv.End.Equal(time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)))
+
+	iv, err = ParseYearQuarter("2020-Q4")
+	assert.NoError(err)
+	assert.True(iv.Start.Equal(time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(iv.End.Equal(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	// round trip
+	d := time.Date(2020, 5, 20, 0, 0, 0, 0, time.UTC)
+	iv, err = ParseYearQuarter(FormatYearQuarter(d))
+	assert.NoError(err)
+	assert.True(iv.Start.Equal(time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(iv.End.Equal(time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)))
+
+	_, err = ParseYearQuarter("2020-Q5")
+	assert.Equal(ErrQuarterRange, err)
+	_, err = ParseYearQuarter("2020-Q0")
+	assert.Equal(ErrQuarterRange, err)
+	_, err = ParseYearQuarter("0000-Q1")
+	assert.Equal(ErrYearRange, err)
+	_, err = ParseYearQuarter("2020-Q12")
+	assert.Error(err)
+	_, err = ParseYearQuarter("2020Q1")
+	assert.Error(err)
+}
+
+func TestISODurationApprox(t *testing.T) {
+	assert := assert.New(t)
+
+	day := 24 * time.Hour
+	month := time.Duration(30.44 * float64(day))
+
+	assert.InDelta(1, ApproxMonths(month), 1e-9)
+	assert.InDelta(12, ApproxMonths(365*day+6*time.Hour), 0.001)
+	assert.InDelta(-0.5, ApproxMonths(-month/2), 1e-9)
+
+	assert.Equal("P1M", FormatDurationApprox(month))
+	assert.Equal("P1M", FormatDurationApprox(30*day))
+	assert.Equal("P1M", FormatDurationApprox(28*day))
+	assert.Equal("-P1M", FormatDurationApprox(-31*day))
+	assert.Equal("P1Y", FormatDurationApprox(365*day))
+	assert.Equal("P1Y2M", FormatDurationApprox(14*month))
+
+	// half an average month is 15.22 days
+	assert.Equal("P1M", FormatDurationApprox(month/2+time.Hour))
+	assert.Equal("P15D", FormatDurationApprox(month/2-time.Hour))
+
+	// under a month
+	assert.Equal("P3D", FormatDurationApprox(3*day+time.Hour))
+	assert.Equal("PT2H0M0S", FormatDurationApprox(2*time.Hour+400*time.Millisecond))
+}
+
+func TestISODurationMinimalFormatting(t *testing.T) {
+	assert := assert.New(t)
+
+	day := 24 * time.Hour
+
+	assert.Equal("P2W", FormatDurationMinimal(14*day))
+	// shorter than PT360H0M0S
+	assert.Equal("P15D", FormatDurationMinimal(15*day))
+	// a tie goes to days
+	assert.Equal("P7D", FormatDurationMinimal(7*day))
+	// not a whole number of weeks
+	assert.Equal("P14DT1M", FormatDurationMinimal(14*day+time.Minute))
+
+	// single units win when they're shorter
+	assert.Equal("PT36H", FormatDurationMinimal(36*time.Hour))
+	assert.Equal("PT90M", FormatDurationMinimal(90*time.Minute))
+	assert.Equal("PT90.5S", FormatDurationMinimal(90500*time.Millisecond))
+	assert.Equal("PT1H", FormatDurationMinimal(time.Hour))
+	assert.Equal("PT1H0.5S", FormatDurationMinimal(time.Hour+500*time.Millisecond))
+	assert.Equal("PT25H", FormatDurationMinimal(25*time.Hour))
+	assert.Equal("PT1501M", FormatDurationMinimal(25*time.Hour+time.Minute))
+
+	assert.Equal("PT0S", FormatDurationMinimal(0))
+	assert.Equal("-P2W", FormatDurationMinimal(-14*day))
+	assert.Equal("-PT90M", FormatDurationMinimal(-90*time.Minute))
+
+	// every form parses back strictly
+	for _, dur := range []time.Duration{14 * day, 15 * day, 36 * time.Hour, 90500 * time.Millisecond, -14 * day} {
+		parsed, err := ParseDuration(FormatDurationMinimal(dur))
+		assert.NoError(err)
+		assert.Equal(dur, parsed)
+	}
+}
+
+func TestParseRFC3339(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, s := range []string{
+		"2020-01-01T12:00:00Z",
+		"2020-01-01T12:00:00.5Z",
+		"2020-01-01T12:00:00.123456789Z",
+		"2020-01-01T12:00:00.1234567891Z",
+		"2020-01-01T12:00:00,5Z",
+		"2020-06-15T08:30:00+05:30",
+		"2020-06-15T08:30:00-07:00",
+		"2020-06-15T08:30:00.25-00:00",
+		"2020-06-15T08:30:00+00:00",
+		"2020-02-29T00:00:00Z",
+		"0000-01-01T00:00:00Z",
+		"9999-12-31T23:59:59.999999999Z",
+		// invalid, one way or another
+		"2021-02-29T00:00:00Z",
+		"2020-13-01T00:00:00Z",
+		"2020-01-01T24:00:00Z",
+		"2020-01-01T23:60:00Z",
+		"2020-01-01T23:59:60Z",
+		"2020-01-01T12:00:00",
+		"2020-01-01T12:00:00.Z",
+		"2020-01-01T12:00:00+0100",
+		"2020-01-01T12:00:00+01",
+		"2020-01-01 12:00:00Z",
+		"2020-1-01T12:00:00Z",
+		"2020-01-01T12:00:00z",
+		"2020-01-01T12:00:00ZZ",
+		"20a0-01-01T12:00:00Z",
+		"",
+	} {
+		expected, expectedErr := time.Parse(time.RFC3339, s)
+		parsed, err := ParseRFC3339(s)
+		assert.Equal(expectedErr, err, s)
+		assert.True(parsed.Equal(expected), s)
+
+		name, offset := parsed.Zone()
+		expectedName, expectedOffset := expected.Zone()
+		assert.Equal(expectedName, name, s)
+		assert.Equal(expectedOffset, offset, s)
+		assert.Equal(expected.Location().String(), parsed.Location().String(), s)
+	}
+}
+
+func BenchmarkParseRFC3339(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ParseRFC3339("2020-06-15T08:30:00.123456789Z")
+	}
+}
+
+func BenchmarkTimeParseRFC3339(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		time.Parse(time.RFC3339, "2020-06-15T08:30:00.123456789Z")
+	}
+}
@@ -0,0 +1,155 @@
+package iso8601
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Time wraps time.Time, marshaling to and from JSON as an ISO 8601 datetime.
+//
+// When unmarshaling, a quoted JSON string is parsed as an ISO 8601 datetime
+// (RFC 3339 layout, fractional seconds allowed), while a bare JSON number is
+// interpreted as seconds since the Unix epoch and yields a UTC time.
+// JSON null leaves the Time untouched.
+type Time struct {
+	time.Time
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t Time) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Format(time.RFC3339Nano))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+
+		parsed, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return err
+		}
+		t.Time = parsed
+		return nil
+	}
+
+	parsed, err := parseEpoch(string(data))
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// parseEpoch interprets a JSON number as seconds since the Unix epoch.
+// Integers are converted exactly; anything else goes through float64.
+// Numbers beyond the int64 seconds time.Unix takes are an error.
+func parseEpoch(number string) (time.Time, error) {
+	if sec, err := strconv.ParseInt(number, 10, 64); err == nil {
+		return time.Unix(sec, 0).UTC(), nil
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return time.Time{}, fmt.Errorf("invalid epoch timestamp %q", number)
+	}
+	// float64(math.MaxInt64) rounds up to 1<<63, which is itself out of range
+	if f < math.MinInt64 || f >= math.MaxInt64 {
+		return time.Time{}, fmt.Errorf("epoch timestamp %q is out of range", number)
+	}
+	sec := math.Floor(f)
+	nsec := math.Round((f - sec) * 1e9)
+	return time.Unix(int64(sec), int64(nsec)).UTC(), nil
+}
+
+// MarshalJSON implements json.Marshaler, writing each interval in the
+// start/end form of Interval.String.
+func (s IntervalSet) MarshalJSON() ([]byte, error) {
+	strs := make([]string, len(s))
+	for i, iv := range s {
+		strs[i] = iv.String()
+	}
+	return json.Marshal(strs)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting any interval form
+// ParseInterval does. The result is normalized; JSON null leaves s untouched.
+func (s *IntervalSet) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+
+	var strs []string
+	if err := json.Unmarshal(data, &strs); err != nil {
+		return err
+	}
+
+	set := make(IntervalSet, len(strs))
+	for i, str := range strs {
+		iv, err := ParseInterval(str)
+		if err != nil {
+			return err
+		}
+		set[i] = iv
+	}
+	*s = set.Normalize()
+	return nil
+}
+
+// Timeout wraps time.Duration, marshaling to JSON as an ISO 8601 duration.
+//
+// When unmarshaling, a quoted JSON string is parsed as an ISO 8601 duration
+// (see ParseDuration), e.g. "PT30S", while a bare JSON number is a number of
+// seconds, e.g. 30 or 0.5. JSON null leaves the Timeout untouched.
+type Timeout struct {
+	time.Duration
+}
+
+// MarshalJSON implements json.Marshaler, writing the duration to the nanosecond.
+func (t Timeout) MarshalJSON() ([]byte, error) {
+	return json.Marshal(FormatDurationWith(t.Duration, 0, Truncate))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Timeout) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+
+		parsed, err := ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		t.Duration = parsed
+		return nil
+	}
+
+	seconds, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("invalid timeout %q", data)
+	}
+	if math.Abs(seconds) >= math.MaxInt64/float64(time.Second) {
+		return ErrDurationRange
+	}
+	t.Duration = secondsDuration(seconds)
+	return nil
+}
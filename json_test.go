@@ -0,0 +1,141 @@
+package iso8601
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTimeJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	var payload struct {
+		At Time `json:"at"`
+	}
+
+	// quoted values are ISO 8601
+	err := json.Unmarshal([]byte(`{"at": "2020-01-01T12:00:00-05:00"}`), &payload)
+	assert.NoError(err)
+	assert.True(payload.At.Equal(time.Date(2020, 1, 1, 17, 0, 0, 0, time.UTC)))
+
+	// bare numbers are Unix seconds
+	err = json.Unmarshal([]byte(`{"at": 1577880000}`), &payload)
+	assert.NoError(err)
+	assert.True(payload.At.Equal(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)))
+
+	err = json.Unmarshal([]byte(`{"at": 1577880000.5}`), &payload)
+	assert.NoError(err)
+	assert.True(payload.At.Equal(time.Date(2020, 1, 1, 12, 0, 0, 500000000, time.UTC)))
+
+	// a quoted number isn't an epoch
+	err = json.Unmarshal([]byte(`{"at": "1577880000"}`), &payload)
+	assert.Error(err)
+
+	err = json.Unmarshal([]byte(`{"at": true}`), &payload)
+	assert.Error(err)
+
+	// numbers beyond int64 seconds are out of range
+	for _, number := range []string{`1e300`, `-1e300`, `9223372036854775808`, `9.3e18`, `-9.3e18`} {
+		payload.At = Time{}
+		err = json.Unmarshal([]byte(`{"at": `+number+`}`), &payload)
+		assert.Error(err, number)
+		assert.True(payload.At.IsZero(), number)
+	}
+	err = json.Unmarshal([]byte(`{"at": 9223372036854775807}`), &payload)
+	assert.NoError(err)
+	assert.Equal(int64(math.MaxInt64), payload.At.Unix())
+	err = json.Unmarshal([]byte(`{"at": -9.2e18}`), &payload)
+	assert.NoError(err)
+	assert.Equal(int64(-9.2e18), payload.At.Unix())
+
+	// marshaling emits ISO 8601
+	payload.At = Time{time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+	data, err := json.Marshal(payload)
+	assert.NoError(err)
+	assert.Equal(`{"at":"2020-01-01T12:00:00Z"}`, string(data))
+}
+
+func TestIntervalSetJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	day := func(d int) time.Time {
+		return time.Date(2020, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	// marshaling keeps the set as is
+	set := IntervalSet{{day(3), day(6)}, {day(1), day(4)}, {day(8), day(9)}}
+	data, err := json.Marshal(set)
+	assert.NoError(err)
+	assert.Equal(`["2020-01-03T00:00:00Z/2020-01-06T00:00:00Z",`+
+		`"2020-01-01T00:00:00Z/2020-01-04T00:00:00Z",`+
+		`"2020-01-08T00:00:00Z/2020-01-09T00:00:00Z"]`, string(data))
+
+	// overlaps are merged on the way back in
+	var decoded IntervalSet
+	err = json.Unmarshal(data, &decoded)
+	assert.NoError(err)
+	assert.Len(decoded, 2)
+	assert.Equal(Interval{day(1), day(6)}.String(), decoded[0].String())
+	assert.Equal(Interval{day(8), day(9)}.String(), decoded[1].String())
+
+	// any interval form is accepted
+	err = json.Unmarshal([]byte(`["2020-01-01T00:00:00Z/P1D", "P1D/2020-01-03T00:00:00Z"]`), &decoded)
+	assert.NoError(err)
+	assert.Len(decoded, 1)
+	assert.Equal(Interval{day(1), day(3)}.String(), decoded[0].String())
+
+	// open starts overlap
+	err = json.Unmarshal([]byte(`["../2020-01-01T00:00:00Z", "../2021-01-01T00:00:00Z"]`), &decoded)
+	assert.NoError(err)
+	assert.Len(decoded, 1)
+	assert.Equal("../2021-01-01T00:00:00Z", decoded[0].String())
+
+	err = json.Unmarshal([]byte(`null`), &decoded)
+	assert.NoError(err)
+	assert.Len(decoded, 1)
+
+	err = json.Unmarshal([]byte(`["2020-01-01T00:00:00Z"]`), &decoded)
+	assert.Error(err)
+	err = json.Unmarshal([]byte(`"2020-01-01T00:00:00Z/P1D"`), &decoded)
+	assert.Error(err)
+}
+
+func TestTimeoutJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	var config struct {
+		Timeout Timeout `json:"timeout"`
+	}
+
+	// strings are ISO 8601, numbers are seconds
+	for _, data := range []string{`{"timeout": "PT30S"}`, `{"timeout": 30}`, `{"timeout": 30.0}`, `{"timeout": "PT0.5M"}`} {
+		config.Timeout = Timeout{}
+		err := json.Unmarshal([]byte(data), &config)
+		assert.NoError(err, data)
+		assert.Equal(30*time.Second, config.Timeout.Duration, data)
+	}
+
+	err := json.Unmarshal([]byte(`{"timeout": 0.25}`), &config)
+	assert.NoError(err)
+	assert.Equal(250*time.Millisecond, config.Timeout.Duration)
+
+	// null is a no-op
+	err = json.Unmarshal([]byte(`{"timeout": null}`), &config)
+	assert.NoError(err)
+	assert.Equal(250*time.Millisecond, config.Timeout.Duration)
+
+	// a quoted number isn't seconds
+	err = json.Unmarshal([]byte(`{"timeout": "30"}`), &config)
+	assert.Error(err)
+	err = json.Unmarshal([]byte(`{"timeout": true}`), &config)
+	assert.Error(err)
+	err = json.Unmarshal([]byte(`{"timeout": 1e300}`), &config)
+	assert.Equal(ErrDurationRange, err)
+
+	config.Timeout = Timeout{90 * time.Second}
+	data, err := json.Marshal(config)
+	assert.NoError(err)
+	assert.Equal(`{"timeout":"PT1M30S"}`, string(data))
+}
@@ -0,0 +1,171 @@
+package iso8601
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// zonelessDateTimeLayout is the layout of an ISO 8601 datetime without a zone designator.
+// time.Parse accepts fractional seconds after the seconds field.
+const zonelessDateTimeLayout = ISOFullDate + "T" + ISOHoursMinutesSeconds
+
+// spaceSeparatorRe matches a date followed by a space in place of the T separator.
+var spaceSeparatorRe = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}) (\d)`)
+
+// Parser holds configuration for parsing ISO 8601 strings.
+// The zero value is ready to use; it parses strictly and assumes UTC
+// for zoneless inputs.
+type Parser struct {
+	// Location is assumed for datetimes that carry no zone designator.
+	// Datetimes with a zone designator keep their own zone.
+	// A nil Location means UTC.
+	Location *time.Location
+
+	// Lenient relaxes parsing to accept common, non-conforming variants:
+	//   - a space in place of the T separating date and time, as emitted
+	//     by databases and permitted by RFC 3339 (2020-01-01 12:00:00Z);
+	//   - durations missing their leading P (1DT1H);
+	//   - durations combining weeks with other components (P1W3DT2H);
+	//   - durations with surrounding whitespace, lowercase designators
+	//     or a comma decimal sign (" pt1h30,5m ").
+	Lenient bool
+
+	// ExpandedYearDigits, if more than 4, is the number of digits in an expanded
+	// year agreed on out of band, such that datetimes may begin with a year of
+	// exactly that many digits and no sign (002020-01-01T00:00:00Z for 6).
+	// Four-digit years are still accepted.
+	ExpandedYearDigits int
+}
+
+// ParseDateTime parses an ISO 8601 datetime of the form YYYY-MM-DDThh:mm:ss,
+// with optional fractional seconds and optional zone designator (see ParseZoned),
+// and returns the resultant golang time.Time instance.
+func (p Parser) ParseDateTime(isoDateTime string) (time.Time, error) {
+	if year, rest, ok := p.splitExpandedYear(isoDateTime); ok {
+		return p.parseExpandedDateTime(year, rest)
+	}
+
+	if p.Lenient {
+		isoDateTime = spaceSeparatorRe.ReplaceAllString(isoDateTime, "${1}T$2")
+	}
+
+	t, err := ParseZoned(isoDateTime)
+	if err == nil {
+		return t, nil
+	}
+
+	loc := p.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t, zonelessErr := time.ParseInLocation(zonelessDateTimeLayout, normalizeFraction(isoDateTime), loc)
+	if zonelessErr != nil {
+		return time.Time{}, err
+	}
+	return t, nil
+}
+
+// splitExpandedYear splits isoDateTime into its expanded year and the rest,
+// starting at the hyphen after the year. ok is false if p has no expanded years
+// or isoDateTime doesn't begin with one.
+func (p Parser) splitExpandedYear(isoDateTime string) (year int, rest string, ok bool) {
+	n := p.ExpandedYearDigits
+	if n <= 4 || len(isoDateTime) <= n || isoDateTime[n] != '-' {
+		return 0, "", false
+	}
+	for _, c := range isoDateTime[:n] {
+		if c < '0' || c > '9' {
+			return 0, "", false
+		}
+	}
+
+	year, err := strconv.Atoi(isoDateTime[:n])
+	if err != nil {
+		return 0, "", false
+	}
+	return year, isoDateTime[n:], true
+}
+
+// parseExpandedDateTime parses a datetime whose expanded year has been split off.
+// The rest is parsed with a stand-in year, a leap year so February 29 gets through,
+// and the real year put back afterwards.
+func (p Parser) parseExpandedDateTime(year int, rest string) (time.Time, error) {
+	p.ExpandedYearDigits = 0
+	t, err := p.ParseDateTime("2000" + rest)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	hour, min, sec := t.Clock()
+	expanded := time.Date(year, t.Month(), t.Day(), hour, min, sec, t.Nanosecond(), t.Location())
+	if expanded.Day() != t.Day() {
+		return time.Time{}, ErrDayRange
+	}
+	return expanded, nil
+}
+
+// ParseDuration parses an ISO 8601 string representing a duration like the
+// package-level ParseDuration, applying the parser's leniency.
+func (p Parser) ParseDuration(isoDuration string) (time.Duration, error) {
+	return parseDuration(p.normalizeDuration(isoDuration), p.Lenient)
+}
+
+// ParseISODuration parses an ISO 8601 string representing a duration like the
+// package-level ParseISODuration, applying the parser's leniency.
+func (p Parser) ParseISODuration(isoDuration string) (Duration, error) {
+	return parseISODuration(p.normalizeDuration(isoDuration), p.Lenient)
+}
+
+// normalizeDuration rewrites the non-conforming duration variants accepted
+// in lenient mode into their strict form.
+func (p Parser) normalizeDuration(isoDuration string) string {
+	if !p.Lenient {
+		return isoDuration
+	}
+
+	isoDuration = strings.ToUpper(strings.TrimSpace(isoDuration))
+	isoDuration = strings.Replace(isoDuration, ",", ".", -1)
+
+	unsigned := strings.TrimPrefix(isoDuration, "-")
+	sign := isoDuration[:len(isoDuration)-len(unsigned)]
+	if unsigned != "" && !strings.HasPrefix(unsigned, "P") {
+		unsigned = "P" + unsigned
+	}
+	return sign + unsigned
+}
+
+// ParseDateTimeInLocation parses an ISO 8601 datetime like Parser.ParseDateTime,
+// assuming loc for inputs without a zone designator.
+func ParseDateTimeInLocation(isoDateTime string, loc *time.Location) (time.Time, error) {
+	return Parser{Location: loc}.ParseDateTime(isoDateTime)
+}
+
+// EqualDateTimeStrings parses a and b as ISO 8601 datetimes with Parser.ParseDateTime,
+// and reports whether they denote the same instant. Representations may differ,
+// e.g. 12:00:00.5Z equals 12:00:00.500Z and 13:00:00.5+01:00.
+func EqualDateTimeStrings(a, b string) (bool, error) {
+	var p Parser
+	ta, err := p.ParseDateTime(a)
+	if err != nil {
+		return false, err
+	}
+	tb, err := p.ParseDateTime(b)
+	if err != nil {
+		return false, err
+	}
+	return ta.Equal(tb), nil
+}
+
+// ParseDurationParam parses the query parameter key of values, e.g. window in
+// ?window=PT1H, as an ISO 8601 duration with ParseDuration. An absent key gives
+// a zero duration and no error, whereas one that's present but empty is malformed.
+// If the key is repeated, the first value is used.
+func ParseDurationParam(values url.Values, key string) (time.Duration, error) {
+	if _, ok := values[key]; !ok {
+		return 0, nil
+	}
+	return ParseDuration(values.Get(key))
+}
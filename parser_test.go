@@ -0,0 +1,212 @@
+package iso8601
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParserLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	chicago, err := time.LoadLocation("America/Chicago")
+	assert.NoError(err)
+
+	// zoneless input takes on the parser's location
+	utc, err := Parser{}.ParseDateTime("2020-01-01T12:00:00")
+	assert.NoError(err)
+	assert.True(utc.Equal(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)))
+
+	local, err := ParseDateTimeInLocation("2020-01-01T12:00:00", chicago)
+	assert.NoError(err)
+	assert.True(local.Equal(time.Date(2020, 1, 1, 18, 0, 0, 0, time.UTC)))
+	assert.Equal(6*time.Hour, local.Sub(utc))
+
+	// zoned input keeps its zone
+	zoned, err := Parser{Location: chicago}.ParseDateTime("2020-01-01T12:00:00Z")
+	assert.NoError(err)
+	assert.True(zoned.Equal(utc))
+
+	zoned, err = Parser{Location: chicago}.ParseDateTime("2020-01-01T12:00:00.5+01:00")
+	assert.NoError(err)
+	assert.True(zoned.Equal(time.Date(2020, 1, 1, 11, 0, 0, 500000000, time.UTC)))
+
+	_, err = Parser{}.ParseDateTime("2020-01-01")
+	assert.Error(err)
+}
+
+func TestParserLenientSpaceSeparator(t *testing.T) {
+	assert := assert.New(t)
+
+	noon := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	parsed, err := Parser{Lenient: true}.ParseDateTime("2020-01-01 12:00:00Z")
+	assert.NoError(err)
+	assert.True(parsed.Equal(noon))
+
+	parsed, err = Parser{Lenient: true}.ParseDateTime("2020-01-01 12:00:00")
+	assert.NoError(err)
+	assert.True(parsed.Equal(noon))
+
+	// strict parsing wants the T
+	_, err = Parser{}.ParseDateTime("2020-01-01 12:00:00Z")
+	assert.Error(err)
+
+	// only a single space between date and time will do
+	_, err = Parser{Lenient: true}.ParseDateTime("2020-01-01  12:00:00Z")
+	assert.Error(err)
+}
+
+func TestParserExpandedYear(t *testing.T) {
+	assert := assert.New(t)
+
+	expanded := Parser{ExpandedYearDigits: 6}
+
+	parsed, err := expanded.ParseDateTime("002020-01-01T12:00:00Z")
+	assert.NoError(err)
+	assert.True(parsed.Equal(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)))
+
+	parsed, err = expanded.ParseDateTime("012021-03-15T12:00:00.5+01:00")
+	assert.NoError(err)
+	assert.True(parsed.Equal(time.Date(12021, 3, 15, 11, 0, 0, 500000000, time.UTC)))
+
+	// zoneless datetimes take the parser's location, as usual
+	chicago, err := time.LoadLocation("America/Chicago")
+	assert.NoError(err)
+	parsed, err = Parser{Location: chicago, ExpandedYearDigits: 6}.ParseDateTime("002020-07-01T12:00:00")
+	assert.NoError(err)
+	assert.True(parsed.Equal(time.Date(2020, 7, 1, 12, 0, 0, 0, chicago)))
+
+	// leap days only in leap years
+	_, err = expanded.ParseDateTime("002024-02-29T00:00:00Z")
+	assert.NoError(err)
+	_, err = expanded.ParseDateTime("002023-02-29T00:00:00Z")
+	assert.Equal(ErrDayRange, err)
+
+	// four digits still work, other widths don't
+	_, err = expanded.ParseDateTime("2020-01-01T12:00:00Z")
+	assert.NoError(err)
+	_, err = expanded.ParseDateTime("02020-01-01T12:00:00Z")
+	assert.Error(err)
+
+	// without the agreement, expanded years are rejected
+	_, err = Parser{}.ParseDateTime("002020-01-01T12:00:00Z")
+	assert.Error(err)
+}
+
+func TestParserLenientDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	lenient := Parser{Lenient: true}
+
+	dur, err := lenient.ParseDuration("1DT1H")
+	assert.NoError(err)
+	assert.Equal(25*time.Hour, dur)
+
+	dur, err = lenient.ParseDuration("-T1H")
+	assert.NoError(err)
+	assert.Equal(-time.Hour, dur)
+
+	d, err := lenient.ParseISODuration("1Y2M")
+	assert.NoError(err)
+	assert.Equal(Duration{Years: 1, Months: 2}, d)
+
+	// conforming input is unaffected
+	dur, err = lenient.ParseDuration("P1DT1H")
+	assert.NoError(err)
+	assert.Equal(25*time.Hour, dur)
+
+	// strict parsing wants the P
+	_, err = Parser{}.ParseDuration("1DT1H")
+	assert.Error(err)
+	_, err = lenient.ParseDuration("")
+	assert.Error(err)
+}
+
+func TestParserLenientDurationSpelling(t *testing.T) {
+	assert := assert.New(t)
+
+	lenient := Parser{Lenient: true}
+
+	dur, err := lenient.ParseDuration("  pt1h30m ")
+	assert.NoError(err)
+	assert.Equal(90*time.Minute, dur)
+
+	dur, err = lenient.ParseDuration("PT1,5S")
+	assert.NoError(err)
+	assert.Equal(1500*time.Millisecond, dur)
+
+	// all at once, without the P
+	d, err := lenient.ParseISODuration("\t-1dt0,5h\n")
+	assert.NoError(err)
+	assert.Equal(Duration{Negative: true, Days: 1, Hours: 0.5}, d)
+
+	// strict parsing rejects every one of them
+	for _, s := range []string{"  pt1h30m ", "pt1h30m", " PT1H30M", "PT1,5S"} {
+		_, err = Parser{}.ParseDuration(s)
+		assert.Error(err, s)
+		_, err = ParseDuration(s)
+		assert.Error(err, s)
+	}
+
+	_, err = lenient.ParseDuration("   ")
+	assert.Error(err)
+	_, err = lenient.ParseDuration("PT1,5,5S")
+	assert.Error(err)
+}
+
+func TestEqualDateTimeStrings(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, b := range []string{
+		"2020-01-01T12:00:00.500Z",
+		"2020-01-01T12:00:00.500000000Z",
+		"2020-01-01T12:00:00,5Z",
+		"2020-01-01T13:00:00.5+01:00",
+		// zoneless means UTC
+		"2020-01-01T12:00:00.5",
+	} {
+		equal, err := EqualDateTimeStrings("2020-01-01T12:00:00.5Z", b)
+		assert.NoError(err, b)
+		assert.True(equal, b)
+	}
+
+	equal, err := EqualDateTimeStrings("2020-01-01T12:00:00.5Z", "2020-01-01T12:00:00.05Z")
+	assert.NoError(err)
+	assert.False(equal)
+
+	_, err = EqualDateTimeStrings("2020-01-01T12:00:00.5Z", "noon")
+	assert.Error(err)
+	_, err = EqualDateTimeStrings("noon", "2020-01-01T12:00:00.5Z")
+	assert.Error(err)
+}
+
+func TestParseDurationParam(t *testing.T) {
+	assert := assert.New(t)
+
+	values, err := url.ParseQuery("window=PT1H&step=P1D&bad=1H&empty=")
+	assert.NoError(err)
+
+	dur, err := ParseDurationParam(values, "window")
+	assert.NoError(err)
+	assert.Equal(time.Hour, dur)
+
+	dur, err = ParseDurationParam(values, "step")
+	assert.NoError(err)
+	assert.Equal(24*time.Hour, dur)
+
+	// absent is zero
+	dur, err = ParseDurationParam(values, "missing")
+	assert.NoError(err)
+	assert.Equal(time.Duration(0), dur)
+	dur, err = ParseDurationParam(nil, "window")
+	assert.NoError(err)
+	assert.Equal(time.Duration(0), dur)
+
+	// malformed or empty isn't
+	_, err = ParseDurationParam(values, "bad")
+	assert.Error(err)
+	_, err = ParseDurationParam(values, "empty")
+	assert.Error(err)
+}
@@ -0,0 +1,86 @@
+package iso8601
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Period represents a whole number of calendar years, months and days,
+// as commonly found in contracts and schedules (e.g. P18M or P2Y6M).
+// It's applied with time.AddDate rather than converted to a fixed length.
+// A negative period has negative fields.
+type Period struct {
+	Years  int
+	Months int
+	Days   int
+}
+
+// ParsePeriod parses an ISO 8601 string representing a duration made up only
+// of whole years, months and days (e.g. P18M, P2Y6M or -P1Y2D),
+// and returns the resultant Period. Weeks (P2W) are taken as 7 days each.
+func ParsePeriod(isoPeriod string) (Period, error) {
+	matches, negative, err := matchDuration(isoPeriod, false)
+	if err != nil {
+		return Period{}, err
+	}
+	if matches[5] != "" || matches[6] != "" || matches[7] != "" {
+		return Period{}, errors.New("period string can't have time components")
+	}
+
+	var values [4]int
+	for i, match := range matches[1:5] {
+		if match == "" {
+			continue
+		}
+		if strings.Contains(match, ".") {
+			return Period{}, errors.New("period string can't have fractional components")
+		}
+
+		values[i], err = strconv.Atoi(match)
+		if err != nil {
+			return Period{}, err
+		}
+		if negative {
+			values[i] = -values[i]
+		}
+	}
+
+	return Period{Years: values[0], Months: values[1], Days: 7*values[2] + values[3]}, nil
+}
+
+// AddTo returns t plus p, as computed by time.AddDate.
+func (p Period) AddTo(t time.Time) time.Time {
+	return t.AddDate(p.Years, p.Months, p.Days)
+}
+
+// times returns p repeated k times.
+func (p Period) times(k int) Period {
+	return Period{Years: k * p.Years, Months: k * p.Months, Days: k * p.Days}
+}
+
+// String returns p in the ISO 8601 designator format, e.g. P2Y6M.
+// A zero period is P0D.
+func (p Period) String() string {
+	if p.Years <= 0 && p.Months <= 0 && p.Days <= 0 && p != (Period{}) {
+		return "-" + Period{Years: -p.Years, Months: -p.Months, Days: -p.Days}.String()
+	}
+
+	var b strings.Builder
+	b.WriteString("P")
+	for _, c := range []struct {
+		value      int
+		designator string
+	}{{p.Years, "Y"}, {p.Months, "M"}, {p.Days, "D"}} {
+		if c.value != 0 {
+			b.WriteString(strconv.Itoa(c.value))
+			b.WriteString(c.designator)
+		}
+	}
+
+	if b.Len() == 1 {
+		return "P0D"
+	}
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package iso8601
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPeriodParsing(t *testing.T) {
+	assert := assert.New(t)
+
+	p, err := ParsePeriod("P18M")
+	assert.NoError(err)
+	assert.Equal(Period{Months: 18}, p)
+
+	jan1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(p.AddTo(jan1).Equal(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)))
+
+	p, err = ParsePeriod("P2Y6M")
+	assert.NoError(err)
+	assert.Equal(Period{Years: 2, Months: 6}, p)
+	assert.True(p.AddTo(jan1).Equal(time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)))
+
+	p, err = ParsePeriod("-P1Y2D")
+	assert.NoError(err)
+	assert.Equal(Period{Years: -1, Days: -2}, p)
+	assert.Equal("-P1Y2D", p.String())
+
+	assert.Equal("P2Y6M", Period{Years: 2, Months: 6}.String())
+	assert.Equal("P0D", Period{}.String())
+
+	p, err = ParsePeriod("P2W")
+	assert.NoError(err)
+	assert.Equal(Period{Days: 14}, p)
+
+	_, err = ParsePeriod("P1MT1H")
+	assert.Error(err)
+	_, err = ParsePeriod("P1.5M")
+	assert.Error(err)
+}
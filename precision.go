@@ -0,0 +1,135 @@
+package iso8601
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Precision is the smallest unit present in a (possibly reduced) ISO 8601 datetime.
+type Precision int
+
+// These are the supported precisions, from coarsest to finest.
+const (
+	PrecisionYear Precision = iota
+	PrecisionMonth
+	PrecisionDay
+	PrecisionHour
+	PrecisionMinute
+	PrecisionSecond
+	PrecisionNanosecond
+)
+
+// precisionNames are the names of the precisions, as used by String and ParsePrecision.
+var precisionNames = [...]string{"year", "month", "day", "hour", "minute", "second", "nanosecond"}
+
+// String returns the name of p, e.g. "month".
+func (p Precision) String() string {
+	if p < 0 || int(p) >= len(precisionNames) {
+		return "Precision(" + strconv.Itoa(int(p)) + ")"
+	}
+	return precisionNames[p]
+}
+
+// ParsePrecision returns the Precision named name, as returned by Precision.String,
+// e.g. "month" for PrecisionMonth. Case is ignored.
+func ParsePrecision(name string) (Precision, error) {
+	for p, precisionName := range precisionNames {
+		if strings.EqualFold(name, precisionName) {
+			return Precision(p), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown precision %q", name)
+}
+
+// reducedLayouts are the layouts tried by ParseReduced, with the precision of each.
+// Layouts with a time of day are tried without a zone designator, and with each
+// zone designator form of zonedLayouts.
+var reducedLayouts = []struct {
+	layout    string
+	precision Precision
+}{
+	{ISOYear, PrecisionYear},
+	{ISOYearMonth, PrecisionMonth},
+	{ISOFullDate, PrecisionDay},
+	{ISOFullDate + "T15", PrecisionHour},
+	{ISOFullDate + "T15Z07:00", PrecisionHour},
+	{ISOFullDate + "T15Z0700", PrecisionHour},
+	{ISOFullDate + "T15Z07", PrecisionHour},
+	{ISOFullDate + "T" + ISOHoursMinutes, PrecisionMinute},
+	{ISOFullDate + "T" + ISOHoursMinutes + "Z07:00", PrecisionMinute},
+	{ISOFullDate + "T" + ISOHoursMinutes + "Z0700", PrecisionMinute},
+	{ISOFullDate + "T" + ISOHoursMinutes + "Z07", PrecisionMinute},
+	{ISOFullDate + "T" + ISOHoursMinutesSeconds, PrecisionSecond},
+	{ISOFullDate + "T" + ISOHoursMinutesSeconds + "Z07:00", PrecisionSecond},
+	{ISOFullDate + "T" + ISOHoursMinutesSeconds + "Z0700", PrecisionSecond},
+	{ISOFullDate + "T" + ISOHoursMinutesSeconds + "Z07", PrecisionSecond},
+}
+
+// ParseReduced parses an ISO 8601 string representing a datetime of any precision,
+// from a year (2020) through a month (2020-03), day, hour, minute and second
+// (2020-03-15T09:30:00Z), and returns the start of the period it denotes along with
+// its precision. Fractional seconds give PrecisionNanosecond.
+// Times of day may have a zone designator in any form ParseZoned accepts;
+// datetimes without one are in UTC.
+func ParseReduced(isoDateTime string) (time.Time, Precision, error) {
+	isoDateTime = normalizeFraction(isoDateTime)
+	if i := strings.IndexByte(isoDateTime, 'T'); i >= 0 && !hasTwoDigitHour(isoDateTime[i+1:]) {
+		return time.Time{}, 0, errors.New("isoDateTime string must have a two-digit hour")
+	}
+
+	for _, reduced := range reducedLayouts {
+		t, err := time.Parse(reduced.layout, isoDateTime)
+		if err != nil {
+			continue
+		}
+
+		if reduced.precision == PrecisionSecond && strings.Contains(isoDateTime, ".") {
+			return t, PrecisionNanosecond, nil
+		}
+		return t, reduced.precision, nil
+	}
+
+	return time.Time{}, 0, errors.New("isoDateTime string is of incorrect format")
+}
+
+// EqualAtPrecision reports whether a and b are the same when compared only down to
+// the given precision, e.g. 2020-03-01 and 2020-03-15 are equal at PrecisionMonth.
+// b is converted to a's location before the fields are compared.
+func EqualAtPrecision(a, b time.Time, p Precision) bool {
+	return TruncateToPeriod(a, p).Equal(TruncateToPeriod(b.In(a.Location()), p))
+}
+
+// TruncateToPeriod returns the start of the period of precision p that t falls in,
+// i.e. t with every field finer than p zeroed (or set to 1 for months and days),
+// e.g. midnight on the first of the month for PrecisionMonth.
+// Fields are taken in t's location, and the result is in t's location.
+func TruncateToPeriod(t time.Time, p Precision) time.Time {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	nsec := t.Nanosecond()
+
+	switch p {
+	case PrecisionYear:
+		month = time.January
+		fallthrough
+	case PrecisionMonth:
+		day = 1
+		fallthrough
+	case PrecisionDay:
+		hour = 0
+		fallthrough
+	case PrecisionHour:
+		min = 0
+		fallthrough
+	case PrecisionMinute:
+		sec = 0
+		fallthrough
+	case PrecisionSecond:
+		nsec = 0
+	}
+
+	return time.Date(year, month, day, hour, min, sec, nsec, t.Location())
+}
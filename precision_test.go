@@ -0,0 +1,111 @@
+package iso8601
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEqualAtPrecision(t *testing.T) {
+	assert := assert.New(t)
+
+	march := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	ides := time.Date(2020, 3, 15, 9, 30, 0, 0, time.UTC)
+
+	assert.True(EqualAtPrecision(march, ides, PrecisionYear))
+	assert.True(EqualAtPrecision(march, ides, PrecisionMonth))
+	assert.False(EqualAtPrecision(march, ides, PrecisionDay))
+	assert.False(EqualAtPrecision(march, ides, PrecisionNanosecond))
+
+	// b is viewed in a's location, so late on Feb 29 in New York is March in UTC
+	ny := time.FixedZone("EST", -5*60*60)
+	assert.True(EqualAtPrecision(march, time.Date(2020, 2, 29, 21, 0, 0, 0, ny), PrecisionMonth))
+	assert.True(EqualAtPrecision(march.Add(time.Second), march.Add(time.Second+time.Millisecond), PrecisionSecond))
+	assert.False(EqualAtPrecision(march.Add(time.Second), march, PrecisionSecond))
+}
+
+func TestReducedParsing(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		iso       string
+		expected  time.Time
+		precision Precision
+	}{
+		{"2020", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), PrecisionYear},
+		{"2020-03", time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), PrecisionMonth},
+		{"2020-03-15", time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC), PrecisionDay},
+		{"2020-03-15T09", time.Date(2020, 3, 15, 9, 0, 0, 0, time.UTC), PrecisionHour},
+		{"2020-03-15T09Z", time.Date(2020, 3, 15, 9, 0, 0, 0, time.UTC), PrecisionHour},
+		{"2020-03-15T09:30", time.Date(2020, 3, 15, 9, 30, 0, 0, time.UTC), PrecisionMinute},
+		{"2020-03-15T10:30+01:00", time.Date(2020, 3, 15, 9, 30, 0, 0, time.UTC), PrecisionMinute},
+		{"2020-03-15T10:30+0100", time.Date(2020, 3, 15, 9, 30, 0, 0, time.UTC), PrecisionMinute},
+		{"2020-03-15T10+01", time.Date(2020, 3, 15, 9, 0, 0, 0, time.UTC), PrecisionHour},
+		{"2020-03-15T04:30:15-05", time.Date(2020, 3, 15, 9, 30, 15, 0, time.UTC), PrecisionSecond},
+		{"2020-03-15T10:30:15.5+0100", time.Date(2020, 3, 15, 9, 30, 15, 500000000, time.UTC), PrecisionNanosecond},
+		{"2020-03-15T09:30:15Z", time.Date(2020, 3, 15, 9, 30, 15, 0, time.UTC), PrecisionSecond},
+		{"2020-03-15T09:30:15.5Z", time.Date(2020, 3, 15, 9, 30, 15, 500000000, time.UTC), PrecisionNanosecond},
+	}
+
+	for _, test := range tests {
+		parsed, precision, err := ParseReduced(test.iso)
+		assert.NoError(err, test.iso)
+		assert.True(test.expected.Equal(parsed), test.iso)
+		assert.Equal(test.precision, precision, test.iso)
+	}
+
+	_, _, err := ParseReduced("2020-3")
+	assert.Error(err)
+	_, _, err = ParseReduced("2020-03-15T")
+	assert.Error(err)
+
+	// hours always have two digits
+	for _, s := range []string{"2020-03-15T9", "2020-03-15T9Z", "2020-03-15T9+01", "2020-03-15T9:30", "2020-03-15T9:30:15Z"} {
+		_, _, err = ParseReduced(s)
+		assert.Error(err, s)
+		_, err = ParseInterval(s + "/P1D")
+		assert.Error(err, s)
+	}
+}
+
+func TestTruncateToPeriod(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := time.Date(2020, 3, 15, 9, 30, 45, 500, time.UTC)
+	assert.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), TruncateToPeriod(ts, PrecisionYear))
+	assert.Equal(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), TruncateToPeriod(ts, PrecisionMonth))
+	assert.Equal(time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC), TruncateToPeriod(ts, PrecisionDay))
+	assert.Equal(time.Date(2020, 3, 15, 9, 0, 0, 0, time.UTC), TruncateToPeriod(ts, PrecisionHour))
+	assert.Equal(time.Date(2020, 3, 15, 9, 30, 45, 0, time.UTC), TruncateToPeriod(ts, PrecisionSecond))
+	assert.Equal(ts, TruncateToPeriod(ts, PrecisionNanosecond))
+
+	// fields are those of t's location: 01:30 UTC on Mar 1 is still February in Chicago
+	chicago, err := time.LoadLocation("America/Chicago")
+	assert.NoError(err)
+	local := time.Date(2020, 3, 1, 1, 30, 0, 0, time.UTC).In(chicago)
+	truncated := TruncateToPeriod(local, PrecisionMonth)
+	assert.True(truncated.Equal(time.Date(2020, 2, 1, 0, 0, 0, 0, chicago)))
+	assert.Equal(chicago, truncated.Location())
+}
+
+func TestPrecisionNames(t *testing.T) {
+	assert := assert.New(t)
+
+	for p := PrecisionYear; p <= PrecisionNanosecond; p++ {
+		parsed, err := ParsePrecision(p.String())
+		assert.NoError(err, p.String())
+		assert.Equal(p, parsed)
+	}
+
+	assert.Equal("month", PrecisionMonth.String())
+	assert.Equal("Precision(7)", Precision(7).String())
+
+	p, err := ParsePrecision("Month")
+	assert.NoError(err)
+	assert.Equal(PrecisionMonth, p)
+
+	_, err = ParsePrecision("fortnight")
+	assert.Error(err)
+	_, err = ParsePrecision("")
+	assert.Error(err)
+}
@@ -0,0 +1,115 @@
+package iso8601
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// monthDayRe matches the recurring annual date forms --MM-DD and --MMDD.
+var monthDayRe = regexp.MustCompile(`^--(\d{2})-?(\d{2})$`)
+
+// ParseMonthDay parses an ISO 8601 string representing a recurring annual date
+// without a year, in either the extended (--MM-DD) or basic (--MMDD) form.
+// --02-29 is accepted, even though it only occurs in leap years.
+func ParseMonthDay(isoMonthDay string) (month, day int, err error) {
+	matches := monthDayRe.FindStringSubmatch(isoMonthDay)
+	if matches == nil {
+		return 0, 0, errors.New("isoMonthDay string is of incorrect format")
+	}
+
+	month, err = strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	if month < 1 || month > 12 {
+		return 0, 0, ErrMonthRange
+	}
+
+	day, err = strconv.Atoi(matches[2])
+	if err != nil {
+		return 0, 0, err
+	}
+	// 2000 is a leap year, so February gets 29 days
+	if day < 1 || day > daysIn(2000, time.Month(month)) {
+		return 0, 0, ErrDayRange
+	}
+
+	return month, day, nil
+}
+
+// daysIn returns the number of days in the given month of the given year.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// NextAnnual returns the next occurrence of the given month and day strictly
+// after the given time, at midnight in after's location.
+// February 29 only occurs in leap years, so it may be several years away.
+// The zero time is returned if the month and day never occur.
+func NextAnnual(month, day int, after time.Time) time.Time {
+	if month < 1 || month > 12 {
+		return time.Time{}
+	}
+
+	// leap days are never more than 8 years apart
+	for year := after.Year(); year <= after.Year()+8; year++ {
+		if day < 1 || day > daysIn(year, time.Month(month)) {
+			continue
+		}
+
+		next := time.Date(year, time.Month(month), day, 0, 0, 0, 0, after.Location())
+		if next.After(after) {
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+// recurringWeekRe matches the recurring week form --Www.
+var recurringWeekRe = regexp.MustCompile(`^--W(\d{2})$`)
+
+// ParseRecurringWeek parses an ISO 8601 string representing a week that recurs
+// every year, in the --Www form (e.g. --W03), and returns the week number.
+// Note: this form comes from less common ISO 8601 profiles rather than the core standard.
+func ParseRecurringWeek(isoWeek string) (int, error) {
+	matches := recurringWeekRe.FindStringSubmatch(isoWeek)
+	if matches == nil {
+		return 0, errors.New("isoWeek string is of incorrect format")
+	}
+
+	week, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, err
+	}
+	if week < MinWeek || week > 53 {
+		return 0, ErrWeekRange
+	}
+
+	return week, nil
+}
+
+// NextAnnualWeek returns the Monday starting the next occurrence of the given
+// ISO week strictly after the given time, at midnight in after's location.
+// Week 53 only occurs in long ISO years, so it may be several years away.
+// The zero time is returned if the week never occurs.
+func NextAnnualWeek(week int, after time.Time) time.Time {
+	year, _ := after.ISOWeek()
+
+	// long ISO years are never more than 7 years apart
+	for ; year <= after.Year()+8; year++ {
+		if week < MinWeek || week > ISOYearWeeks(year) {
+			continue
+		}
+
+		monday := weekDate(year, week, 1)
+		next := time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, after.Location())
+		if next.After(after) {
+			return next
+		}
+	}
+
+	return time.Time{}
+}
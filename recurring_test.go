@@ -0,0 +1,90 @@
+package iso8601
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMonthDayParsing(t *testing.T) {
+	assert := assert.New(t)
+
+	month, day, err := ParseMonthDay("--12-25")
+	assert.NoError(err)
+	assert.Equal(12, month)
+	assert.Equal(25, day)
+
+	// basic form
+	month, day, err = ParseMonthDay("--1225")
+	assert.NoError(err)
+	assert.Equal(12, month)
+	assert.Equal(25, day)
+
+	// leap day is fine without a year
+	month, day, err = ParseMonthDay("--02-29")
+	assert.NoError(err)
+	assert.Equal(2, month)
+	assert.Equal(29, day)
+
+	_, _, err = ParseMonthDay("--02-30")
+	assert.Equal(ErrDayRange, err)
+	_, _, err = ParseMonthDay("--13-01")
+	assert.Equal(ErrMonthRange, err)
+	_, _, err = ParseMonthDay("12-25")
+	assert.Error(err)
+}
+
+func TestNextAnnual(t *testing.T) {
+	assert := assert.New(t)
+
+	after := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(NextAnnual(12, 25, after).Equal(time.Date(2020, 12, 25, 0, 0, 0, 0, time.UTC)))
+
+	// strictly after: Christmas day itself rolls to the following year
+	after = time.Date(2020, 12, 25, 0, 0, 0, 0, time.UTC)
+	assert.True(NextAnnual(12, 25, after).Equal(time.Date(2021, 12, 25, 0, 0, 0, 0, time.UTC)))
+
+	// leap day skips ahead to the next leap year
+	after = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(NextAnnual(2, 29, after).Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)))
+
+	// 2100 isn't a leap year
+	after = time.Date(2096, 3, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(NextAnnual(2, 29, after).Equal(time.Date(2104, 2, 29, 0, 0, 0, 0, time.UTC)))
+
+	// the result is in after's location
+	loc := time.FixedZone("UTC+9", 9*60*60)
+	after = time.Date(2020, 6, 1, 0, 0, 0, 0, loc)
+	assert.True(NextAnnual(12, 25, after).Equal(time.Date(2020, 12, 25, 0, 0, 0, 0, loc)))
+
+	assert.True(NextAnnual(2, 30, after).IsZero())
+
+	// months out of range never occur, rather than spilling into the next year
+	assert.True(NextAnnual(13, 1, time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)).IsZero())
+	assert.True(NextAnnual(0, 15, time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)).IsZero())
+	assert.True(NextAnnual(-1, 1, after).IsZero())
+}
+
+func TestRecurringWeek(t *testing.T) {
+	assert := assert.New(t)
+
+	week, err := ParseRecurringWeek("--W03")
+	assert.NoError(err)
+	assert.Equal(3, week)
+
+	_, err = ParseRecurringWeek("--W54")
+	assert.Equal(ErrWeekRange, err)
+	_, err = ParseRecurringWeek("--W3")
+	assert.Error(err)
+
+	// 2021-W03 begins on Jan 18, 2021
+	after := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(NextAnnualWeek(3, after).Equal(time.Date(2021, 1, 18, 0, 0, 0, 0, time.UTC)))
+
+	// already past it, so 2022-W03 which begins on Jan 17, 2022
+	after = time.Date(2021, 1, 18, 0, 0, 0, 0, time.UTC)
+	assert.True(NextAnnualWeek(3, after).Equal(time.Date(2022, 1, 17, 0, 0, 0, 0, time.UTC)))
+
+	// week 53 waits for the next long year, 2026-W53 begins on Dec 28, 2026
+	assert.True(NextAnnualWeek(53, after).Equal(time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,140 @@
+package iso8601
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RepeatingInterval represents an ISO 8601 repeating interval, such as
+// R3/2020-01-01T00:00:00Z/P1D: a number of consecutive intervals, each
+// Period long, the first of which begins at Start.
+type RepeatingInterval struct {
+	// Repetitions is the number of intervals, or -1 if unbounded.
+	Repetitions int
+	Start       time.Time
+	Period      Duration
+}
+
+// repetitionsRe matches the repetitions part of a repeating interval,
+// Rn, or R or R-1 for an unbounded number.
+var repetitionsRe = regexp.MustCompile(`^R(\d+|-1)?$`)
+
+// ParseRepeatingInterval parses an ISO 8601 string representing a repeating
+// interval, and returns the resultant RepeatingInterval. The intervals may be
+// given as start/duration (R5/2020-01-01T00:00:00Z/P1M) or as start/end, in which
+// case each interval is as long as the calendar duration from start to end
+// (see Between). Start and end are as for ParseInterval, and the intervals must
+// move forward in time. Repetitions are written as Rn, or as R or R-1 when unbounded.
+func ParseRepeatingInterval(isoRepeatingInterval string) (RepeatingInterval, error) {
+	parts := strings.SplitN(isoRepeatingInterval, "/", 2)
+	if len(parts) != 2 {
+		return RepeatingInterval{}, errors.New("isoRepeatingInterval string is of incorrect format")
+	}
+
+	matches := repetitionsRe.FindStringSubmatch(strings.TrimSpace(parts[0]))
+	if matches == nil {
+		return RepeatingInterval{}, errors.New("isoRepeatingInterval string has invalid repetitions")
+	}
+	repetitions := -1
+	if matches[1] != "" {
+		n, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return RepeatingInterval{}, err
+		}
+		repetitions = n
+	}
+
+	ends := strings.Split(parts[1], "/")
+	if len(ends) != 2 {
+		return RepeatingInterval{}, errors.New("isoRepeatingInterval string is of incorrect format")
+	}
+	if strings.HasPrefix(strings.TrimSpace(ends[0]), "P") {
+		return RepeatingInterval{}, errors.New("repeating intervals must begin with a start")
+	}
+
+	iv, err := ParseInterval(parts[1])
+	if err != nil {
+		return RepeatingInterval{}, err
+	}
+	if iv.StartOpen() || iv.EndOpen() {
+		return RepeatingInterval{}, errors.New("repeating intervals can't have open ends")
+	}
+
+	period := Between(iv.Start, iv.End)
+	if second := strings.TrimSpace(ends[1]); strings.HasPrefix(second, "P") {
+		period, err = ParseISODuration(second)
+		if err != nil {
+			return RepeatingInterval{}, err
+		}
+	}
+	if !period.AddTo(iv.Start).After(iv.Start) {
+		return RepeatingInterval{}, errors.New("repeating intervals must move forward in time")
+	}
+
+	return RepeatingInterval{Repetitions: repetitions, Start: iv.Start, Period: period}, nil
+}
+
+// Unbounded reports whether r repeats forever.
+func (r RepeatingInterval) Unbounded() bool {
+	return r.Repetitions < 0
+}
+
+// occurrence returns the start of the k-th interval of r, counting from 0.
+// It's computed from Start rather than the previous occurrence, so calendar
+// periods don't drift: with P1M from January 31, the third start is March 31,
+// even though the second overflows February into March 2.
+func (r RepeatingInterval) occurrence(k int) time.Time {
+	return r.Period.Scale(float64(k)).AddTo(r.Start)
+}
+
+// Expand returns the start of each interval of r, in order.
+// An unbounded r is cut off after limit starts; limit is ignored otherwise.
+func (r RepeatingInterval) Expand(limit int) []time.Time {
+	n := r.Repetitions
+	if r.Unbounded() {
+		n = limit
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	starts := make([]time.Time, 0, n)
+	for k := 0; k < n; k++ {
+		starts = append(starts, r.occurrence(k))
+	}
+	return starts
+}
+
+// Until returns the start of each interval of r that begins before deadline,
+// in order, stopping there even if r is unbounded. As with Interval, the deadline
+// itself is excluded, so a daily recurrence from January 1 with a deadline of
+// January 4 gives January 1, 2 and 3.
+func (r RepeatingInterval) Until(deadline time.Time) []time.Time {
+	var starts []time.Time
+	for k := 0; r.Unbounded() || k < r.Repetitions; k++ {
+		start := r.occurrence(k)
+		if !start.Before(deadline) {
+			break
+		}
+		// a Period that doesn't move forward would never reach the deadline
+		if k > 0 && !start.After(starts[k-1]) {
+			break
+		}
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// ExpandRecurrence parses an ISO 8601 repeating interval like ParseRepeatingInterval,
+// e.g. R3/2020-01-01T00:00:00Z/P1D, and returns the start of each of its intervals.
+// An unbounded recurrence is cut off after limit starts; limit is ignored otherwise.
+func ExpandRecurrence(isoRepeatingInterval string, limit int) ([]time.Time, error) {
+	r, err := ParseRepeatingInterval(isoRepeatingInterval)
+	if err != nil {
+		return nil, err
+	}
+	return r.Expand(limit), nil
+}
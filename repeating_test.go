@@ -0,0 +1,103 @@
+package iso8601
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRepeatingIntervalParsing(t *testing.T) {
+	assert := assert.New(t)
+
+	jan1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	r, err := ParseRepeatingInterval("R5/2020-01-01T00:00:00Z/P1M")
+	assert.NoError(err)
+	assert.Equal(5, r.Repetitions)
+	assert.False(r.Unbounded())
+	assert.True(r.Start.Equal(jan1))
+	assert.Equal(Duration{Months: 1}, r.Period)
+
+	// start/end gives the period
+	r, err = ParseRepeatingInterval("R2/2020-01-01T00:00:00Z/2020-01-01T01:30:00Z")
+	assert.NoError(err)
+	assert.Equal(Duration{Hours: 1, Minutes: 30}, r.Period)
+
+	for _, s := range []string{"R/2020-01-01T00:00:00Z/P1D", "R-1/2020-01-01T00:00:00Z/P1D"} {
+		r, err = ParseRepeatingInterval(s)
+		assert.NoError(err, s)
+		assert.True(r.Unbounded(), s)
+	}
+
+	for _, s := range []string{
+		"2020-01-01T00:00:00Z/P1D",
+		"Rx/2020-01-01T00:00:00Z/P1D",
+		"R-2/2020-01-01T00:00:00Z/P1D",
+		"R3/P1D/2020-01-01T00:00:00Z",
+		"R3/2020-01-01T00:00:00Z/..",
+		"R3/2020-01-01T00:00:00Z",
+		"R3/2020-01-01T00:00:00Z/PT0S",
+		"R3/2020-01-02T00:00:00Z/2020-01-01T00:00:00Z",
+	} {
+		_, err = ParseRepeatingInterval(s)
+		assert.Error(err, s)
+	}
+}
+
+func TestExpandRecurrence(t *testing.T) {
+	assert := assert.New(t)
+
+	day := func(m time.Month, d int) time.Time {
+		return time.Date(2020, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	starts, err := ExpandRecurrence("R3/2020-01-01T00:00:00Z/P1D", 100)
+	assert.NoError(err)
+	assert.Equal([]time.Time{day(1, 1), day(1, 2), day(1, 3)}, starts)
+
+	// the limit only applies to unbounded recurrences
+	starts, err = ExpandRecurrence("R3/2020-01-01T00:00:00Z/P1D", 1)
+	assert.NoError(err)
+	assert.Len(starts, 3)
+	starts, err = ExpandRecurrence("R/2020-01-01T00:00:00Z/P1D", 2)
+	assert.NoError(err)
+	assert.Equal([]time.Time{day(1, 1), day(1, 2)}, starts)
+
+	// months don't drift after a short one
+	starts, err = ExpandRecurrence("R4/2020-01-31T00:00:00Z/P1M", 0)
+	assert.NoError(err)
+	assert.Equal([]time.Time{day(1, 31), day(3, 2), day(3, 31), day(5, 1)}, starts)
+
+	starts, err = ExpandRecurrence("R/2020-01-01T00:00:00Z/P1D", -1)
+	assert.NoError(err)
+	assert.Empty(starts)
+	starts, err = ExpandRecurrence("R0/2020-01-01T00:00:00Z/P1D", 10)
+	assert.NoError(err)
+	assert.Empty(starts)
+
+	_, err = ExpandRecurrence("R3/P1D", 10)
+	assert.Error(err)
+}
+
+func TestRepeatingIntervalUntil(t *testing.T) {
+	assert := assert.New(t)
+
+	jan1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	r, err := ParseRepeatingInterval("R/2020-01-01T00:00:00Z/P1D")
+	assert.NoError(err)
+	assert.Equal([]time.Time{jan1, jan1.AddDate(0, 0, 1), jan1.AddDate(0, 0, 2)}, r.Until(jan1.AddDate(0, 0, 3)))
+	assert.Len(r.Until(jan1.AddDate(0, 0, 3).Add(time.Nanosecond)), 4)
+
+	// a bounded recurrence stops at its last interval
+	r, err = ParseRepeatingInterval("R2/2020-01-01T00:00:00Z/P1D")
+	assert.NoError(err)
+	assert.Len(r.Until(jan1.AddDate(1, 0, 0)), 2)
+
+	// nothing before the first start
+	assert.Empty(r.Until(jan1))
+
+	// a period going nowhere doesn't run away
+	r = RepeatingInterval{Repetitions: -1, Start: jan1}
+	assert.Equal([]time.Time{jan1}, r.Until(jan1.AddDate(0, 0, 3)))
+}